@@ -0,0 +1,90 @@
+// Package gobinderr turns gin binding failures into consistent JSON error
+// responses: validation failures become 422 with per-field details keyed by
+// JSON field name, anything else (malformed JSON, wrong types) a 400.
+//
+// Usage:
+//
+//	if err := c.ShouldBindJSON(&req); err != nil {
+//		gobinderr.Respond(c, err)
+//		return
+//	}
+package gobinderr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one failed validation rule on a request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func init() {
+	// Report fields by their JSON names rather than Go struct field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+	}
+}
+
+// Respond writes the response for a failed ShouldBind call.
+func Respond(c *gin.Context, err error) {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	details := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		details = append(details, FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	RespondFields(c, details...)
+}
+
+// RespondFields writes a 422 with the given field errors, for validation
+// done outside the binder that should look the same to clients.
+func RespondFields(c *gin.Context, details ...FieldError) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": details})
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), fe.Param())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fe.Field())
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", fe.Field())
+	default:
+		return fmt.Sprintf("%s failed the %q rule", fe.Field(), fe.Tag())
+	}
+}
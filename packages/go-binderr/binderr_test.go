@@ -0,0 +1,98 @@
+package gobinderr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type createRequest struct {
+	Name          string `json:"name" binding:"required,min=1,max=255"`
+	ExpiresInDays *int   `json:"expiresInDays" binding:"omitempty,min=1"`
+}
+
+func bindRequest(body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.POST("/items", func(c *gin.Context) {
+		var req createRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Respond(c, err)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeFieldErrors(t *testing.T, w *httptest.ResponseRecorder) map[string]FieldError {
+	t.Helper()
+	var body struct {
+		Errors []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	byField := make(map[string]FieldError)
+	for _, fe := range body.Errors {
+		byField[fe.Field] = fe
+	}
+	return byField
+}
+
+func TestRespondReportsFieldDetails(t *testing.T) {
+	w := bindRequest(`{"expiresInDays": -1}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	byField := decodeFieldErrors(t, w)
+	if fe, ok := byField["name"]; !ok || fe.Rule != "required" || fe.Message != "name is required" {
+		t.Errorf("expected required error on name, got %+v", byField)
+	}
+	if fe, ok := byField["expiresInDays"]; !ok || fe.Rule != "min" {
+		t.Errorf("expected min error on expiresInDays, got %+v", byField)
+	}
+}
+
+func TestRespondFallsBackForMalformedJSON(t *testing.T) {
+	w := bindRequest(`{"name":`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if _, ok := body["error"]; !ok {
+		t.Errorf("expected an error message, got %v", body)
+	}
+}
+
+func TestRespondFields(t *testing.T) {
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		RespondFields(c, FieldError{Field: "url", Rule: "scheme", Message: "url must use https"})
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", w.Code)
+	}
+	if fe := decodeFieldErrors(t, w)["url"]; fe.Rule != "scheme" || fe.Message != "url must use https" {
+		t.Errorf("unexpected field error: %+v", fe)
+	}
+}
@@ -0,0 +1,131 @@
+// Package gocors provides CORS middleware for gin services.
+//
+// Usage:
+//
+//	router.Use(gocors.Middleware(gocors.Config{
+//		AllowedOrigins: []string{"https://admin.quckapp.com", "*.quckapp.com"},
+//	}))
+package gocors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Defaults applied when a Config field is empty.
+var (
+	DefaultAllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	DefaultAllowedHeaders = []string{"Origin", "Content-Type", "Authorization", "X-API-Key"}
+)
+
+// DefaultMaxAge is how long browsers may cache a preflight response, in seconds.
+const DefaultMaxAge = 86400
+
+// Config holds CORS settings.
+type Config struct {
+	// AllowedOrigins are matched exactly, or by a wildcard subdomain pattern
+	// such as "*.quckapp.com" or "https://*.quckapp.com:8443". A single "*"
+	// allows any origin and is intended for local development only. With no
+	// origins configured, no cross-origin access is granted.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         int
+}
+
+// Middleware answers preflight requests with 204 and sets the CORS headers
+// on every response. Vary: Origin is always sent, so shared caches never
+// serve one origin's response to another.
+func Middleware(cfg Config) gin.HandlerFunc {
+	if len(cfg.AllowedMethods) == 0 {
+		cfg.AllowedMethods = DefaultAllowedMethods
+	}
+	if len(cfg.AllowedHeaders) == 0 {
+		cfg.AllowedHeaders = DefaultAllowedHeaders
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = DefaultMaxAge
+	}
+	allowAny := false
+	for _, o := range cfg.AllowedOrigins {
+		if o == "*" {
+			allowAny = true
+		}
+	}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(cfg.MaxAge)
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Origin")
+
+		origin := c.GetHeader("Origin")
+		if allowAny {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else if origin != "" && OriginAllowed(origin, cfg.AllowedOrigins) {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		c.Header("Access-Control-Allow-Methods", methods)
+		c.Header("Access-Control-Allow-Headers", headers)
+		c.Header("Access-Control-Max-Age", maxAge)
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// OriginAllowed reports whether origin matches one of the allowed patterns.
+// A wildcard pattern without a port matches the origin on any port; a
+// pattern with a port matches only that port.
+func OriginAllowed(origin string, allowedOrigins []string) bool {
+	origin = strings.ToLower(origin)
+	originScheme, originHost := splitOrigin(origin)
+	originHostname, originPort := splitPort(originHost)
+
+	for _, pattern := range allowedOrigins {
+		pattern = strings.ToLower(pattern)
+		if pattern == origin {
+			return true
+		}
+
+		scheme, host := splitOrigin(pattern)
+		if !strings.HasPrefix(host, "*.") {
+			continue
+		}
+		if scheme != "" && scheme != originScheme {
+			continue
+		}
+		hostname, port := splitPort(host)
+		if port != "" && port != originPort {
+			continue
+		}
+		suffix := hostname[1:] // ".quckapp.com"
+		if strings.HasSuffix(originHostname, suffix) && len(originHostname) > len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitOrigin splits "https://app.quckapp.com" into its scheme and host.
+// A value without a scheme is returned as the host.
+func splitOrigin(v string) (scheme, host string) {
+	if i := strings.Index(v, "://"); i >= 0 {
+		return v[:i], v[i+3:]
+	}
+	return "", v
+}
+
+// splitPort splits "app.quckapp.com:8443" into hostname and port.
+func splitPort(host string) (hostname, port string) {
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		return host[:i], host[i+1:]
+	}
+	return host, ""
+}
@@ -0,0 +1,114 @@
+package gocors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func corsRequest(allowed []string, method, origin string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(Middleware(Config{AllowedOrigins: allowed}))
+	router.GET("/api/v1/config/qa/env", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(method, "/api/v1/config/qa/env", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAllowedOriginIsEchoed(t *testing.T) {
+	w := corsRequest([]string{"https://admin.quckapp.com"}, http.MethodGet, "https://admin.quckapp.com")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://admin.quckapp.com" {
+		t.Errorf("expected origin to be echoed, got %q", got)
+	}
+}
+
+func TestDisallowedOriginGetsNoAllowHeader(t *testing.T) {
+	allowed := []string{"https://admin.quckapp.com"}
+
+	for _, origin := range []string{"https://evil.example.com", "http://admin.quckapp.com"} {
+		w := corsRequest(allowed, http.MethodGet, origin)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("origin %s: expected no Access-Control-Allow-Origin, got %q", origin, got)
+		}
+	}
+
+	w := corsRequest(nil, http.MethodOptions, "https://admin.quckapp.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no Access-Control-Allow-Origin with an empty allow-list, got %q", got)
+	}
+}
+
+func TestVaryOriginOnEveryResponse(t *testing.T) {
+	allowed := []string{"https://admin.quckapp.com"}
+	cases := []struct {
+		name   string
+		method string
+		origin string
+	}{
+		{"allowed", http.MethodGet, "https://admin.quckapp.com"},
+		{"disallowed", http.MethodGet, "https://evil.example.com"},
+		{"preflight", http.MethodOptions, "https://admin.quckapp.com"},
+		{"no origin", http.MethodGet, ""},
+	}
+	for _, tc := range cases {
+		w := corsRequest(allowed, tc.method, tc.origin)
+		if got := w.Header().Get("Vary"); got != "Origin" {
+			t.Errorf("%s: expected Vary: Origin, got %q", tc.name, got)
+		}
+	}
+}
+
+func TestWildcardSubdomain(t *testing.T) {
+	cases := []struct {
+		pattern string
+		origin  string
+		allowed bool
+	}{
+		{"*.quckapp.com", "https://app.quckapp.com", true},
+		{"*.quckapp.com", "http://staging.admin.quckapp.com", true},
+		{"*.quckapp.com", "https://quckapp.com", false},
+		{"*.quckapp.com", "https://evilquckapp.com", false},
+		{"*.quckapp.com", "https://app.quckapp.com.evil.io", false},
+		{"https://*.quckapp.com", "https://app.quckapp.com", true},
+		{"https://*.quckapp.com", "http://app.quckapp.com", false},
+		{"*.quckapp.com", "https://app.quckapp.com:8443", true},
+		{"*.quckapp.com", "https://app.quckapp.com.evil.io:443", false},
+		{"https://*.quckapp.local:3000", "https://web.quckapp.local:3000", true},
+		{"https://*.quckapp.local:3000", "https://web.quckapp.local:4000", false},
+		{"https://*.quckapp.local:3000", "https://web.quckapp.local", false},
+	}
+
+	for _, tc := range cases {
+		w := corsRequest([]string{tc.pattern}, http.MethodGet, tc.origin)
+		got := w.Header().Get("Access-Control-Allow-Origin")
+		if tc.allowed && got != tc.origin {
+			t.Errorf("%s should allow %s, got %q", tc.pattern, tc.origin, got)
+		}
+		if !tc.allowed && got != "" {
+			t.Errorf("%s should not allow %s, got %q", tc.pattern, tc.origin, got)
+		}
+	}
+}
+
+func TestExplicitWildcard(t *testing.T) {
+	w := corsRequest([]string{"*"}, http.MethodOptions, "http://localhost:3000")
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for preflight, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected *, got %q", got)
+	}
+}
@@ -0,0 +1,225 @@
+// Package godbhealth monitors a database connection in the background and
+// exposes its readiness for health probes.
+//
+// It works with anything that can be pinged: *sql.DB and *sqlx.DB satisfy
+// Pinger directly, and other clients can be adapted with PingerFunc.
+//
+// Usage:
+//
+//	checker := godbhealth.New(db, godbhealth.Config{Interval: 10 * time.Second})
+//	checker.Start(ctx)
+//	router.GET("/ready", gin.WrapH(checker.Handler()))
+//
+// Handler leaves ping errors out of the response unless Config.ExposeErrors
+// is set; use Config.OnChange to log them.
+//
+//	// MongoDB:
+//	checker := godbhealth.New(godbhealth.PingerFunc(func(ctx context.Context) error {
+//		return client.Ping(ctx, nil)
+//	}), godbhealth.Config{})
+package godbhealth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults applied when a Config field is zero.
+const (
+	DefaultInterval   = 10 * time.Second
+	DefaultTimeout    = 2 * time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// Pinger is satisfied by *sql.DB and *sqlx.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingerFunc adapts a function, such as a Mongo client's Ping, to Pinger.
+type PingerFunc func(ctx context.Context) error
+
+func (f PingerFunc) PingContext(ctx context.Context) error { return f(ctx) }
+
+// Config holds checker settings.
+type Config struct {
+	// Interval between pings while healthy.
+	Interval time.Duration
+
+	// Timeout bounds each ping.
+	Timeout time.Duration
+
+	// MaxBackoff caps the delay between retries while unhealthy. Retries
+	// start at Interval and double after each consecutive failure.
+	MaxBackoff time.Duration
+
+	// FailureThreshold is the number of consecutive failed pings before the
+	// checker reports not ready. Defaults to 1.
+	FailureThreshold int
+
+	// Reconnect, if set, is called after each failed ping, for clients that
+	// do not re-establish connections on their own.
+	Reconnect func(ctx context.Context) error
+
+	// OnChange, if set, is called whenever readiness flips. It is the place
+	// to log the cause, since Handler does not report it by default.
+	OnChange func(ready bool, err error)
+
+	// ExposeErrors includes the last ping error in Handler responses. Leave
+	// it off for unauthenticated probes: driver errors can name hosts and
+	// users.
+	ExposeErrors bool
+}
+
+// Checker tracks the readiness of one connection.
+type Checker struct {
+	pinger Pinger
+	cfg    Config
+
+	mu       sync.RWMutex
+	ready    bool
+	lastErr  error
+	failures int
+	checked  time.Time
+}
+
+// New creates a Checker. It reports not ready until the first successful ping.
+func New(p Pinger, cfg Config) *Checker {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+	return &Checker{pinger: p, cfg: cfg}
+}
+
+// Start pings immediately and then in the background until ctx is done.
+func (c *Checker) Start(ctx context.Context) {
+	c.Check(ctx)
+	go func() {
+		for {
+			timer := time.NewTimer(c.nextDelay())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				c.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Check runs one ping, attempting a reconnect on failure, and updates readiness.
+func (c *Checker) Check(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	err := c.pinger.PingContext(pingCtx)
+	cancel()
+
+	if err != nil && c.cfg.Reconnect != nil {
+		reconnectCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		if c.cfg.Reconnect(reconnectCtx) == nil {
+			pingCtx, cancelPing := context.WithTimeout(ctx, c.cfg.Timeout)
+			err = c.pinger.PingContext(pingCtx)
+			cancelPing()
+		}
+		cancel()
+	}
+
+	c.record(err)
+	return err
+}
+
+// Ready reports whether the connection is currently usable.
+func (c *Checker) Ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ready
+}
+
+// LastError returns the error from the most recent failed ping, or nil if the
+// most recent ping succeeded.
+func (c *Checker) LastError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastErr
+}
+
+// Handler serves 200 when ready and 503 otherwise, for readiness probes. The
+// body carries the last error only when Config.ExposeErrors is set.
+func (c *Checker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.RLock()
+		ready, lastErr, checked := c.ready, c.lastErr, c.checked
+		c.mu.RUnlock()
+
+		body := map[string]interface{}{"status": "up", "checkedAt": checked}
+		status := http.StatusOK
+		if !ready {
+			status = http.StatusServiceUnavailable
+			body["status"] = "down"
+			if lastErr != nil && c.cfg.ExposeErrors {
+				body["error"] = lastErr.Error()
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+func (c *Checker) record(err error) {
+	c.mu.Lock()
+	wasReady := c.ready
+	c.checked = time.Now()
+	c.lastErr = err
+	if err == nil {
+		c.failures = 0
+		c.ready = true
+	} else {
+		c.failures++
+		if c.failures >= c.cfg.FailureThreshold {
+			c.ready = false
+		}
+	}
+	ready := c.ready
+	onChange := c.cfg.OnChange
+	c.mu.Unlock()
+
+	if onChange != nil && ready != wasReady {
+		onChange(ready, err)
+	}
+}
+
+// nextDelay is Interval while healthy, and doubles per consecutive failure
+// up to MaxBackoff while unhealthy.
+func (c *Checker) nextDelay() time.Duration {
+	c.mu.RLock()
+	failures := c.failures
+	c.mu.RUnlock()
+	return backoff(c.cfg.Interval, c.cfg.MaxBackoff, failures)
+}
+
+func backoff(base, max time.Duration, failures int) time.Duration {
+	d := base
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
@@ -0,0 +1,183 @@
+package godbhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyDB fails while down is set.
+type flakyDB struct {
+	mu    sync.Mutex
+	down  bool
+	pings int
+}
+
+func (f *flakyDB) PingContext(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pings++
+	if f.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (f *flakyDB) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func TestReadyFlipsOnFailureAndRecovery(t *testing.T) {
+	db := &flakyDB{}
+	var changes []bool
+	checker := New(db, Config{OnChange: func(ready bool, err error) { changes = append(changes, ready) }})
+	ctx := context.Background()
+
+	if checker.Ready() {
+		t.Fatal("expected not ready before the first check")
+	}
+
+	checker.Check(ctx)
+	if !checker.Ready() {
+		t.Fatal("expected ready after a successful ping")
+	}
+
+	db.setDown(true)
+	if err := checker.Check(ctx); err == nil {
+		t.Fatal("expected ping error while down")
+	}
+	if checker.Ready() {
+		t.Fatal("expected not ready after a failed ping")
+	}
+	if checker.LastError() == nil {
+		t.Error("expected LastError to report the failure")
+	}
+
+	db.setDown(false)
+	checker.Check(ctx)
+	if !checker.Ready() {
+		t.Fatal("expected ready again after recovery")
+	}
+	if checker.LastError() != nil {
+		t.Errorf("expected LastError cleared, got %v", checker.LastError())
+	}
+
+	if len(changes) != 3 || !changes[0] || changes[1] || !changes[2] {
+		t.Errorf("expected transitions [true false true], got %v", changes)
+	}
+}
+
+func TestFailureThreshold(t *testing.T) {
+	db := &flakyDB{}
+	checker := New(db, Config{FailureThreshold: 3})
+	ctx := context.Background()
+	checker.Check(ctx)
+
+	db.setDown(true)
+	checker.Check(ctx)
+	checker.Check(ctx)
+	if !checker.Ready() {
+		t.Fatal("expected to stay ready below the failure threshold")
+	}
+	checker.Check(ctx)
+	if checker.Ready() {
+		t.Fatal("expected not ready at the failure threshold")
+	}
+}
+
+func TestReconnectIsAttempted(t *testing.T) {
+	db := &flakyDB{down: true}
+	reconnects := 0
+	checker := New(db, Config{Reconnect: func(ctx context.Context) error {
+		reconnects++
+		db.setDown(false)
+		return nil
+	}})
+
+	if err := checker.Check(context.Background()); err != nil {
+		t.Fatalf("expected reconnect to restore the connection, got %v", err)
+	}
+	if reconnects != 1 || !checker.Ready() {
+		t.Errorf("expected one reconnect and ready, got %d reconnects ready=%v", reconnects, checker.Ready())
+	}
+}
+
+func TestBackgroundLoopRecovers(t *testing.T) {
+	db := &flakyDB{down: true}
+	checker := New(db, Config{Interval: 5 * time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checker.Start(ctx)
+	if checker.Ready() {
+		t.Fatal("expected not ready while the database is down")
+	}
+
+	db.setDown(false)
+	deadline := time.Now().Add(time.Second)
+	for !checker.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the background loop to detect recovery")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := time.Second, 10*time.Second
+	cases := map[int]time.Duration{0: time.Second, 1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second, 5: max, 50: max}
+	for failures, want := range cases {
+		if got := backoff(base, max, failures); got != want {
+			t.Errorf("backoff after %d failures: expected %v, got %v", failures, want, got)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	db := &flakyDB{down: true}
+	checker := New(db, Config{})
+	checker.Check(context.Background())
+
+	w := httptest.NewRecorder()
+	checker.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when not ready, got %d", w.Code)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse body: %v", err)
+	}
+	if _, ok := body["error"]; body["status"] != "down" || ok {
+		t.Errorf("expected status down without the error, got %v", body)
+	}
+
+	db.setDown(false)
+	checker.Check(context.Background())
+	w = httptest.NewRecorder()
+	checker.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 when ready, got %d", w.Code)
+	}
+}
+
+func TestHandlerExposeErrors(t *testing.T) {
+	checker := New(&flakyDB{down: true}, Config{ExposeErrors: true})
+	checker.Check(context.Background())
+
+	w := httptest.NewRecorder()
+	checker.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse body: %v", err)
+	}
+	if body["error"] != "connection refused" {
+		t.Errorf("expected the error when opted in, got %v", body)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/quckapp/go-dbhealth
+
+go 1.21
@@ -0,0 +1,172 @@
+// Package gopagination parses list pagination parameters from a Gin request
+// and builds the standard paginated response envelope, so every service
+// applies the same defaults, caps and query parameter names.
+//
+// Supported query parameters:
+//   - limit: page size, defaulted and clamped to the configured cap
+//   - offset: zero-based row offset
+//   - page: one-based page number, used when offset is absent
+//   - cursor: opaque cursor for keyset pagination, passed through unchanged
+//
+// Usage:
+//
+//	p := gopagination.Parse(c, gopagination.Config{DefaultLimit: 20, MaxLimit: 100})
+//	items, total, err := repo.List(p.Limit, p.Offset)
+//	c.JSON(http.StatusOK, gopagination.NewResponse(items, p, total))
+package gopagination
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Defaults applied when a Config field is zero.
+const (
+	DefaultLimit = 20
+	DefaultMax   = 100
+
+	// MaxOffset bounds the row offset so a huge page or offset cannot
+	// overflow or reach the database as a nonsensical value.
+	MaxOffset = math.MaxInt32
+)
+
+// Config holds per-endpoint pagination defaults.
+type Config struct {
+	// DefaultLimit is the page size used when limit is missing or invalid.
+	DefaultLimit int
+
+	// MaxLimit caps the page size a caller may request.
+	MaxLimit int
+}
+
+// Params is the parsed pagination request.
+type Params struct {
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Page   int    `json:"page"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Meta describes the returned page.
+type Meta struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Page       int    `json:"page"`
+	Total      int64  `json:"total"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Response is the standard envelope for list endpoints.
+type Response[T any] struct {
+	Data       []T  `json:"data"`
+	Pagination Meta `json:"pagination"`
+}
+
+// Parse reads limit, offset, page and cursor from the query string. Missing,
+// malformed or negative values fall back to defaults, and limit is clamped to
+// cfg.MaxLimit. Offsets are clamped to MaxOffset. When both offset and page
+// are given, offset wins.
+func Parse(c *gin.Context, cfg Config) Params {
+	cfg = cfg.withDefaults()
+
+	limit := queryInt(c, "limit", cfg.DefaultLimit)
+	if limit <= 0 {
+		limit = cfg.DefaultLimit
+	}
+	if limit > cfg.MaxLimit {
+		limit = cfg.MaxLimit
+	}
+
+	p := Params{Limit: limit, Cursor: c.Query("cursor")}
+	if _, ok := c.GetQuery("offset"); ok {
+		p.Offset = queryInt(c, "offset", 0)
+		if p.Offset < 0 {
+			p.Offset = 0
+		}
+		if p.Offset > MaxOffset {
+			p.Offset = MaxOffset
+		}
+		p.Page = PageForOffset(p.Offset, limit)
+	} else {
+		p.Page = queryInt(c, "page", 1)
+		if p.Page < 1 {
+			p.Page = 1
+		}
+		p.Offset = OffsetForPage(p.Page, limit)
+		p.Page = PageForOffset(p.Offset, limit)
+	}
+	return p
+}
+
+// OffsetForPage converts a one-based page number to a row offset, clamped
+// to [0, MaxOffset].
+func OffsetForPage(page, limit int) int {
+	if page < 1 || limit <= 0 {
+		return 0
+	}
+	if page-1 > MaxOffset/limit {
+		return MaxOffset
+	}
+	return (page - 1) * limit
+}
+
+// PageForOffset converts a row offset to the one-based page containing it.
+func PageForOffset(offset, limit int) int {
+	if limit <= 0 {
+		return 1
+	}
+	return offset/limit + 1
+}
+
+// NewResponse wraps one page of items with its pagination metadata.
+func NewResponse[T any](items []T, p Params, total int64) Response[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Response[T]{
+		Data: items,
+		Pagination: Meta{
+			Limit:   p.Limit,
+			Offset:  p.Offset,
+			Page:    p.Page,
+			Total:   total,
+			HasMore: int64(p.Offset+len(items)) < total,
+		},
+	}
+}
+
+// WithNextCursor sets the cursor for the following page in keyset-paginated
+// responses. An empty cursor means there are no more results.
+func (r Response[T]) WithNextCursor(cursor string) Response[T] {
+	r.Pagination.NextCursor = cursor
+	r.Pagination.HasMore = cursor != ""
+	return r
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.DefaultLimit <= 0 {
+		cfg.DefaultLimit = DefaultLimit
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = DefaultMax
+	}
+	if cfg.DefaultLimit > cfg.MaxLimit {
+		cfg.DefaultLimit = cfg.MaxLimit
+	}
+	return cfg
+}
+
+func queryInt(c *gin.Context, key string, fallback int) int {
+	v, ok := c.GetQuery(key)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
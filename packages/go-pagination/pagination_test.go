@@ -0,0 +1,124 @@
+package gopagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func parseQuery(query string, cfg Config) Params {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/files?"+query, nil)
+	return Parse(c, cfg)
+}
+
+func TestParseDefaults(t *testing.T) {
+	p := parseQuery("", Config{DefaultLimit: 50, MaxLimit: 200})
+	if p.Limit != 50 || p.Offset != 0 || p.Page != 1 || p.Cursor != "" {
+		t.Errorf("unexpected defaults: %+v", p)
+	}
+
+	p = parseQuery("", Config{})
+	if p.Limit != DefaultLimit {
+		t.Errorf("expected package default limit %d, got %d", DefaultLimit, p.Limit)
+	}
+
+	p = parseQuery("limit=abc&offset=-5", Config{DefaultLimit: 20, MaxLimit: 100})
+	if p.Limit != 20 || p.Offset != 0 {
+		t.Errorf("expected invalid values to fall back to defaults, got %+v", p)
+	}
+}
+
+func TestParseClampsLimit(t *testing.T) {
+	cfg := Config{DefaultLimit: 20, MaxLimit: 100}
+
+	if p := parseQuery("limit=1000", cfg); p.Limit != 100 {
+		t.Errorf("expected limit clamped to 100, got %d", p.Limit)
+	}
+	if p := parseQuery("limit=0", cfg); p.Limit != 20 {
+		t.Errorf("expected zero limit to use default, got %d", p.Limit)
+	}
+	if p := parseQuery("limit=35", cfg); p.Limit != 35 {
+		t.Errorf("expected limit 35, got %d", p.Limit)
+	}
+}
+
+func TestParsePageOffsetConversion(t *testing.T) {
+	cfg := Config{DefaultLimit: 20, MaxLimit: 100}
+
+	p := parseQuery("page=3&limit=25", cfg)
+	if p.Offset != 50 || p.Page != 3 {
+		t.Errorf("expected page 3 to map to offset 50, got %+v", p)
+	}
+
+	p = parseQuery("offset=50&limit=25", cfg)
+	if p.Page != 3 {
+		t.Errorf("expected offset 50 to map to page 3, got %+v", p)
+	}
+
+	p = parseQuery("offset=10&page=4&limit=25", cfg)
+	if p.Offset != 10 || p.Page != 1 {
+		t.Errorf("expected offset to win over page, got %+v", p)
+	}
+
+	if p := parseQuery("page=0", cfg); p.Page != 1 || p.Offset != 0 {
+		t.Errorf("expected page 0 to clamp to page 1, got %+v", p)
+	}
+}
+
+func TestParseClampsHugeOffset(t *testing.T) {
+	cfg := Config{DefaultLimit: 20, MaxLimit: 100}
+
+	for _, query := range []string{
+		"page=9223372036854775807&limit=100",
+		"offset=9223372036854775807",
+	} {
+		p := parseQuery(query, cfg)
+		if p.Offset < 0 || p.Offset > MaxOffset {
+			t.Errorf("%s: expected offset within [0, %d], got %d", query, MaxOffset, p.Offset)
+		}
+		if p.Page < 1 {
+			t.Errorf("%s: expected a positive page, got %d", query, p.Page)
+		}
+	}
+
+	if got := OffsetForPage(1<<62, 100); got != MaxOffset {
+		t.Errorf("expected OffsetForPage to clamp to MaxOffset, got %d", got)
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	p := parseQuery("cursor=eyJpZCI6NDJ9", Config{})
+	if p.Cursor != "eyJpZCI6NDJ9" {
+		t.Errorf("expected cursor to pass through, got %q", p.Cursor)
+	}
+}
+
+func TestNewResponse(t *testing.T) {
+	p := Params{Limit: 2, Offset: 2, Page: 2}
+
+	resp := NewResponse([]string{"c", "d"}, p, 5)
+	if !resp.Pagination.HasMore || resp.Pagination.Total != 5 || resp.Pagination.Page != 2 {
+		t.Errorf("unexpected pagination meta: %+v", resp.Pagination)
+	}
+
+	last := NewResponse([]string{"e"}, Params{Limit: 2, Offset: 4, Page: 3}, 5)
+	if last.Pagination.HasMore {
+		t.Error("expected no more results on the last page")
+	}
+
+	empty := NewResponse[string](nil, p, 0)
+	if empty.Data == nil {
+		t.Error("expected empty data to serialize as [] rather than null")
+	}
+
+	keyset := NewResponse([]string{"a"}, Params{Limit: 1}, 0).WithNextCursor("next")
+	if !keyset.Pagination.HasMore || keyset.Pagination.NextCursor != "next" {
+		t.Errorf("unexpected keyset meta: %+v", keyset.Pagination)
+	}
+}
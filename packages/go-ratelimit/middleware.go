@@ -0,0 +1,89 @@
+package goratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc derives the rate-limit key for a request. Returning "" skips
+// limiting for that request.
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP limits by client IP.
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByHeader limits by the value of a request header, such as X-API-Key.
+// The value is hashed so credentials never appear in Redis key names.
+func KeyByHeader(name string) KeyFunc {
+	return func(c *gin.Context) string {
+		if v := c.GetHeader(name); v != "" {
+			sum := sha256.Sum256([]byte(v))
+			return "header:" + hex.EncodeToString(sum[:])
+		}
+		return ""
+	}
+}
+
+// KeyByContext limits by a value set in the Gin context by earlier
+// middleware, such as the authenticated user id.
+func KeyByContext(key string) KeyFunc {
+	return func(c *gin.Context) string {
+		if v := c.GetString(key); v != "" {
+			return key + ":" + v
+		}
+		return ""
+	}
+}
+
+// Middleware enforces the limiter for every request with a non-empty key.
+// It sets X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+// (unix seconds) on every checked response, and answers 429 with Retry-After
+// once the limit is exceeded.
+func Middleware(l *Limiter, keyFunc KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		res, err := l.Allow(c.Request.Context(), key)
+		if err != nil {
+			_ = c.Error(err)
+			if l.cfg.FailClosed {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "rate limiter unavailable"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		reset := l.cfg.Now().Add(res.ResetAfter)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(int64(math.Ceil(float64(reset.UnixMilli())/1000)), 10))
+
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	s := int(math.Ceil(d.Seconds()))
+	if s < 1 {
+		return 1
+	}
+	return s
+}
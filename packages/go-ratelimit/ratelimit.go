@@ -0,0 +1,229 @@
+// Package goratelimit provides Redis-backed rate limiting for Gin services.
+//
+// Two strategies are supported:
+//   - SlidingWindow allows Limit requests in any Window-long period.
+//   - TokenBucket allows bursts of up to Limit requests and refills Limit
+//     tokens evenly over each Window.
+//
+// Limits are tracked per key, where the key comes from a caller-provided
+// function (client IP, user id, API key, ...). Each check is a single atomic
+// Lua script, so replicas sharing a Redis instance share limits.
+//
+// Usage:
+//
+//	limiter := goratelimit.New(redisClient, goratelimit.Config{
+//		Strategy: goratelimit.SlidingWindow,
+//		Limit:    30,
+//		Window:   time.Minute,
+//		Prefix:   "file-service:upload",
+//	})
+//	router.POST("/upload", goratelimit.Middleware(limiter, goratelimit.KeyByIP), handler)
+package goratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Strategy selects the limiting algorithm.
+type Strategy string
+
+const (
+	SlidingWindow Strategy = "sliding_window"
+	TokenBucket   Strategy = "token_bucket"
+)
+
+// Config holds limiter settings.
+type Config struct {
+	// Strategy is the limiting algorithm. Defaults to SlidingWindow.
+	Strategy Strategy
+
+	// Limit is the number of requests allowed per Window (the bucket
+	// capacity for TokenBucket).
+	Limit int
+
+	// Window is the sliding window length, or the time to refill a full
+	// bucket for TokenBucket.
+	Window time.Duration
+
+	// Prefix namespaces Redis keys, e.g. "file-service:upload".
+	Prefix string
+
+	// FailClosed rejects requests when Redis is unavailable. By default the
+	// limiter fails open and lets requests through.
+	FailClosed bool
+
+	// Now overrides the clock; used in tests.
+	Now func() time.Time
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // zero when allowed
+	ResetAfter time.Duration // until the limit is fully restored
+}
+
+// Limiter checks and records requests against a Redis-backed limit.
+type Limiter struct {
+	client redis.Scripter
+	cfg    Config
+	seq    atomic.Uint64
+
+	// instance keeps sliding-window members unique across replicas that
+	// share a Redis and record requests in the same millisecond.
+	instance string
+}
+
+// New creates a Limiter. It panics on a non-positive Limit or Window, which
+// are programming errors.
+func New(client redis.Scripter, cfg Config) *Limiter {
+	if cfg.Limit <= 0 || cfg.Window <= 0 {
+		panic("goratelimit: Limit and Window must be positive")
+	}
+	if cfg.Strategy == "" {
+		cfg.Strategy = SlidingWindow
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Limiter{client: client, cfg: cfg, instance: newInstanceID()}
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to the clock; collisions then only undercount.
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Allow records one request for key and reports whether it is within the limit.
+func (l *Limiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.cfg.Now().UnixMilli()
+	window := l.cfg.Window.Milliseconds()
+	redisKey := l.redisKey(key)
+
+	var (
+		res []interface{}
+		err error
+	)
+	switch l.cfg.Strategy {
+	case SlidingWindow:
+		member := strconv.FormatInt(now, 10) + "-" + l.instance + "-" + strconv.FormatUint(l.seq.Add(1), 10)
+		res, err = slidingWindowScript.Run(ctx, l.client, []string{redisKey}, now, window, l.cfg.Limit, member).Slice()
+	case TokenBucket:
+		res, err = tokenBucketScript.Run(ctx, l.client, []string{redisKey}, now, window, l.cfg.Limit).Slice()
+	default:
+		return Result{}, fmt.Errorf("goratelimit: unknown strategy %q", l.cfg.Strategy)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	if len(res) != 4 {
+		return Result{}, errors.New("goratelimit: unexpected script result")
+	}
+
+	return Result{
+		Allowed:    toInt64(res[0]) == 1,
+		Limit:      l.cfg.Limit,
+		Remaining:  int(toInt64(res[1])),
+		RetryAfter: time.Duration(toInt64(res[2])) * time.Millisecond,
+		ResetAfter: time.Duration(toInt64(res[3])) * time.Millisecond,
+	}, nil
+}
+
+func (l *Limiter) redisKey(key string) string {
+	prefix := l.cfg.Prefix
+	if prefix == "" {
+		prefix = "ratelimit"
+	}
+	return prefix + ":" + string(l.cfg.Strategy) + ":" + key
+}
+
+func toInt64(v interface{}) int64 {
+	n, _ := v.(int64)
+	return n
+}
+
+// slidingWindowScript keeps one sorted-set member per request, scored by its
+// timestamp, and counts the members inside the window.
+//
+// Returns {allowed, remaining, retry_after_ms, reset_after_ms}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", key, window)
+
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+local reset = 0
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+local retry = 0
+if allowed == 0 then
+	retry = reset
+end
+return {allowed, limit - count, retry, reset}
+`)
+
+// tokenBucketScript refills the bucket from the time elapsed since the last
+// request, then takes one token if available.
+//
+// Returns {allowed, remaining, retry_after_ms, reset_after_ms}.
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local capacity = tonumber(ARGV[3])
+local rate = capacity / window
+
+local state = redis.call("HMGET", key, "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call("HSET", key, "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", key, window)
+
+local reset = math.ceil((capacity - tokens) / rate)
+return {allowed, math.floor(tokens), retry, reset}
+`)
@@ -0,0 +1,198 @@
+package goratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) Now() time.Time          { return f.t }
+func (f *fakeClock) Advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func setup(t *testing.T, cfg Config) (*gin.Engine, *fakeClock, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	clock := &fakeClock{t: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	cfg.Now = clock.Now
+	limiter := New(client, cfg)
+
+	router := gin.New()
+	router.Use(Middleware(limiter, KeyByHeader("X-API-Key")))
+	router.GET("/api/v1/files", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router, clock, mr
+}
+
+func send(router *gin.Engine, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/api/v1/files", nil)
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSlidingWindowEnforcesLimit(t *testing.T) {
+	router, _, _ := setup(t, Config{Strategy: SlidingWindow, Limit: 3, Window: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		if w := send(router, "key-a"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+	}
+	if w := send(router, "key-a"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the limit is exceeded, got %d", w.Code)
+	}
+
+	// Keys are limited independently.
+	if w := send(router, "key-b"); w.Code != http.StatusOK {
+		t.Errorf("expected a different key to be allowed, got %d", w.Code)
+	}
+	// Requests without a key are not limited.
+	if w := send(router, ""); w.Code != http.StatusOK {
+		t.Errorf("expected keyless request to pass, got %d", w.Code)
+	}
+}
+
+func TestSlidingWindowResets(t *testing.T) {
+	router, clock, _ := setup(t, Config{Strategy: SlidingWindow, Limit: 2, Window: time.Minute})
+
+	send(router, "key-a")
+	clock.Advance(30 * time.Second)
+	send(router, "key-a")
+	if w := send(router, "key-a"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+
+	// The first request leaves the window after 60s; the second is still in it.
+	clock.Advance(31 * time.Second)
+	if w := send(router, "key-a"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 after the oldest request left the window, got %d", w.Code)
+	}
+	if w := send(router, "key-a"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 while the window is full again, got %d", w.Code)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	router, clock, _ := setup(t, Config{Strategy: SlidingWindow, Limit: 2, Window: time.Minute})
+
+	w := send(router, "key-a")
+	if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+		t.Errorf("expected X-RateLimit-Limit 2, got %q", got)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "1" {
+		t.Errorf("expected X-RateLimit-Remaining 1, got %q", got)
+	}
+	wantReset := strconv.FormatInt(clock.Now().Add(time.Minute).Unix(), 10)
+	if got := w.Header().Get("X-RateLimit-Reset"); got != wantReset {
+		t.Errorf("expected X-RateLimit-Reset %s, got %q", wantReset, got)
+	}
+	if got := w.Header().Get("Retry-After"); got != "" {
+		t.Errorf("expected no Retry-After on an allowed request, got %q", got)
+	}
+
+	clock.Advance(20 * time.Second)
+	send(router, "key-a")
+	w = send(router, "key-a")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("expected X-RateLimit-Remaining 0, got %q", got)
+	}
+	if got := w.Header().Get("Retry-After"); got != "40" {
+		t.Errorf("expected Retry-After 40, got %q", got)
+	}
+}
+
+func TestTokenBucketRefills(t *testing.T) {
+	// 4 tokens refilled over 4s: one token per second.
+	router, clock, _ := setup(t, Config{Strategy: TokenBucket, Limit: 4, Window: 4 * time.Second})
+
+	for i := 0; i < 4; i++ {
+		if w := send(router, "key-a"); w.Code != http.StatusOK {
+			t.Fatalf("burst request %d: expected 200, got %d", i+1, w.Code)
+		}
+	}
+	w := send(router, "key-a")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 with an empty bucket, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("expected Retry-After 1, got %q", got)
+	}
+
+	clock.Advance(time.Second)
+	if w := send(router, "key-a"); w.Code != http.StatusOK {
+		t.Fatalf("expected a refilled token after 1s, got %d", w.Code)
+	}
+	if w := send(router, "key-a"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after using the refilled token, got %d", w.Code)
+	}
+}
+
+func TestRedisFailure(t *testing.T) {
+	router, _, mr := setup(t, Config{Limit: 1, Window: time.Minute})
+	mr.Close()
+	if w := send(router, "key-a"); w.Code != http.StatusOK {
+		t.Errorf("expected fail-open by default, got %d", w.Code)
+	}
+
+	router, _, mr = setup(t, Config{Limit: 1, Window: time.Minute, FailClosed: true})
+	mr.Close()
+	if w := send(router, "key-a"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 when failing closed, got %d", w.Code)
+	}
+}
+
+func TestSlidingWindowCountsReplicasInSameMillisecond(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	clock := &fakeClock{t: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	cfg := Config{Limit: 2, Window: time.Minute, Now: clock.Now}
+	replicaA, replicaB := New(client, cfg), New(client, cfg)
+	ctx := context.Background()
+
+	// Both replicas are on their first request at the same instant.
+	for _, l := range []*Limiter{replicaA, replicaB} {
+		if res, err := l.Allow(ctx, "user-1"); err != nil || !res.Allowed {
+			t.Fatalf("expected request allowed, got %+v err=%v", res, err)
+		}
+	}
+	if res, _ := replicaA.Allow(ctx, "user-1"); res.Allowed {
+		t.Error("expected the third request across replicas to be limited")
+	}
+}
+
+func TestKeyByHeaderHashesValue(t *testing.T) {
+	router, _, mr := setup(t, Config{Limit: 5, Window: time.Minute})
+	send(router, "qk_secret_api_key")
+
+	keys := mr.Keys()
+	if len(keys) != 1 {
+		t.Fatalf("expected one rate-limit key, got %v", keys)
+	}
+	if strings.Contains(keys[0], "qk_secret_api_key") {
+		t.Errorf("expected the header value hashed, got key %q", keys[0])
+	}
+}
@@ -0,0 +1,195 @@
+// Package goreqlimit provides gin middleware that bounds request bodies and
+// handler run time.
+//
+// Usage:
+//
+//	router.Use(goreqlimit.MaxBodySize(10 << 20))
+//	router.Use(goreqlimit.Timeout(30 * time.Second))
+//
+// Timeout cannot stop a handler that ignores its context; keep
+// http.Server.WriteTimeout set as the hard bound on a request.
+package goreqlimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodySize rejects requests whose body exceeds limit bytes with 413.
+//
+// Requests that declare a Content-Length over the limit are rejected before
+// the handler runs. Bodies of unknown length are wrapped with
+// http.MaxBytesReader, so reading past the limit fails; whatever the handler
+// then responds, e.g. 400 from a failed bind, is replaced with the 413.
+// Anything the handler wrote before reading the body is sent unchanged.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			abortTooLarge(c, limit)
+			return
+		}
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, limit)}
+		c.Request.Body = body
+		w := &bodyLimitWriter{ResponseWriter: c.Writer, body: body, limit: limit}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if body.exceeded && !c.Writer.Written() {
+			abortTooLarge(c, limit)
+		}
+	}
+}
+
+// limitedBody records whether a read hit the MaxBodySize limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if IsBodyTooLarge(err) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// bodyLimitWriter replaces the handler's response with 413 once the body
+// has gone over the limit.
+type bodyLimitWriter struct {
+	gin.ResponseWriter
+	body  *limitedBody
+	limit int64
+}
+
+func (w *bodyLimitWriter) replaced() bool {
+	if !w.body.exceeded {
+		return false
+	}
+	if !w.ResponseWriter.Written() {
+		w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.ResponseWriter.WriteHeader(http.StatusRequestEntityTooLarge)
+		_, _ = w.ResponseWriter.WriteString(`{"error":"request body too large","limit":` + strconv.FormatInt(w.limit, 10) + "}")
+	}
+	return true
+}
+
+func (w *bodyLimitWriter) WriteHeader(code int) {
+	if w.body.exceeded {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bodyLimitWriter) WriteHeaderNow() {
+	if w.replaced() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *bodyLimitWriter) Write(b []byte) (int, error) {
+	if w.replaced() {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyLimitWriter) WriteString(s string) (int, error) {
+	if w.replaced() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IsBodyTooLarge reports whether err came from reading past a MaxBodySize limit.
+func IsBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+func abortTooLarge(c *gin.Context, limit int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error": "request body too large",
+		"limit": limit,
+	})
+}
+
+// Timeout sets a deadline of d on the request context and responds with 503
+// if the handler has not finished by then.
+//
+// Cancellation is cooperative: handlers stop early only if they honour
+// c.Request.Context(), as database drivers and HTTP clients do. The 503 is
+// written when the handler returns, so a handler that ignores the context
+// holds the request until it finishes and only then gets the 503; bound
+// those with http.Server.WriteTimeout. Anything a handler writes after the
+// deadline is discarded in favour of the 503.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		w := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "request timed out",
+			})
+		}
+	}
+}
+
+// timeoutWriter drops writes once the deadline has passed, so the timeout
+// response is not mixed with a late handler response.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+func (w *timeoutWriter) expired() bool {
+	return w.ctx.Err() != nil && !w.ResponseWriter.Written()
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	if w.expired() {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
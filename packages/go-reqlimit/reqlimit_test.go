@@ -0,0 +1,158 @@
+package goreqlimit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func echoRouter(mw gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	r.Use(mw)
+	r.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if IsBodyTooLarge(err) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	})
+	return r
+}
+
+func TestMaxBodySizeAllowsSmallBody(t *testing.T) {
+	r := echoRouter(MaxBodySize(16))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello")))
+
+	if w.Code != http.StatusOK || w.Body.String() != "hello" {
+		t.Errorf("expected 200 hello, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestMaxBodySizeRejectsOversizedBody(t *testing.T) {
+	r := echoRouter(MaxBodySize(16))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 64))))
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
+
+func TestMaxBodySizeRejectsOversizedBodyOfUnknownLength(t *testing.T) {
+	r := echoRouter(MaxBodySize(16))
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 64)))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
+
+func TestMaxBodySizeReplacesHandlerErrorWith413(t *testing.T) {
+	r := gin.New()
+	r.Use(MaxBodySize(16))
+	r.POST("/bind", func(c *gin.Context) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		// A typical handler: any bind failure is a 400.
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": req.Name})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/bind", strings.NewReader(`{"name":"`+strings.Repeat("x", 64)+`"}`))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Body.String() != `{"error":"request body too large","limit":16}` {
+		t.Errorf("expected only the 413 body, got %s", w.Body.String())
+	}
+}
+
+func TestTimeoutCancelsSlowHandler(t *testing.T) {
+	r := gin.New()
+	r.Use(Timeout(20 * time.Millisecond))
+	r.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.JSON(http.StatusInternalServerError, gin.H{"error": c.Request.Context().Err().Error()})
+		case <-time.After(time.Second):
+			c.JSON(http.StatusOK, gin.H{"data": "done"})
+		}
+	})
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	elapsed := time.Since(start)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "request timed out") {
+		t.Errorf("expected timeout body, got %s", w.Body.String())
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("expected the handler to be cancelled at the deadline, took %v", elapsed)
+	}
+}
+
+func TestTimeoutPassesFastHandler(t *testing.T) {
+	r := gin.New()
+	r.Use(Timeout(time.Second))
+	r.GET("/fast", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"data": "ok"})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), "ok") {
+		t.Errorf("expected 201 ok, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTimeoutWaitsForUncooperativeHandler(t *testing.T) {
+	r := gin.New()
+	r.Use(Timeout(10 * time.Millisecond))
+	r.GET("/stuck", func(c *gin.Context) {
+		// Ignores the request context entirely.
+		time.Sleep(60 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"data": "late"})
+	})
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stuck", nil))
+
+	// Documented behaviour: the 503 only goes out once the handler returns.
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("expected the request held until the handler returned, took %v", elapsed)
+	}
+	if w.Code != http.StatusServiceUnavailable || strings.Contains(w.Body.String(), "late") {
+		t.Errorf("expected only the 503, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,127 @@
+// Package gorequestid provides Gin middleware that assigns every request a
+// correlation id and propagates it through logs, responses and Kafka events.
+//
+// An incoming X-Request-ID header is reused when present and well-formed;
+// otherwise a new UUID is generated. The id is echoed in the response header,
+// stored in the Gin context and in the request's context.Context, so it can be
+// read from handlers as well as from code that only receives a ctx.
+//
+// Usage:
+//
+//	router.Use(gorequestid.Middleware())
+//
+//	// In handlers:
+//	logger.WithFields(gorequestid.LogrusFields(c)).Info("file uploaded")
+//
+//	// When publishing:
+//	msg.Headers = append(msg.Headers, gorequestid.KafkaHeader(ctx))
+package gorequestid
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// HeaderName is the HTTP and Kafka header carrying the request id.
+const HeaderName = "X-Request-ID"
+
+// ContextKeyRequestID is the Gin context key holding the request id.
+const ContextKeyRequestID = "request_id"
+
+// FieldName is the structured log field name for the request id. zap users
+// can log it with zap.String(gorequestid.FieldName, gorequestid.RequestID(c)).
+const FieldName = "request_id"
+
+// maxIDLength bounds accepted incoming ids so clients cannot bloat logs.
+const maxIDLength = 128
+
+type ctxKey struct{}
+
+// Middleware reads X-Request-ID from the request or generates one, then
+// exposes it through RequestID, FromContext and the response header.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderName)
+		if !validID(id) {
+			id = NewID()
+		}
+
+		c.Set(ContextKeyRequestID, id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Header(HeaderName, id)
+		c.Next()
+	}
+}
+
+// NewID returns a fresh request id.
+func NewID() string {
+	return uuid.NewString()
+}
+
+// RequestID returns the request id set by Middleware, or "" if it has not run.
+func RequestID(c *gin.Context) string {
+	if v, ok := c.Get(ContextKeyRequestID); ok {
+		if id, ok := v.(string); ok {
+			return id
+		}
+	}
+	return FromContext(c.Request.Context())
+}
+
+// WithRequestID returns a copy of ctx carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request id stored in ctx, or "".
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// LogrusFields returns the request id as a logrus field set.
+func LogrusFields(c *gin.Context) logrus.Fields {
+	return logrus.Fields{FieldName: RequestID(c)}
+}
+
+// KafkaHeader returns a Kafka message header carrying the request id in ctx,
+// generating a new id when ctx has none.
+func KafkaHeader(ctx context.Context) kafka.Header {
+	id := FromContext(ctx)
+	if id == "" {
+		id = NewID()
+	}
+	return kafka.Header{Key: HeaderName, Value: []byte(id)}
+}
+
+// ContextFromKafka returns ctx carrying the request id found in the message
+// headers, or a newly generated one, so async processing stays correlated.
+func ContextFromKafka(ctx context.Context, headers []kafka.Header) context.Context {
+	for _, h := range headers {
+		if h.Key == HeaderName && validID(string(h.Value)) {
+			return WithRequestID(ctx, string(h.Value))
+		}
+	}
+	return WithRequestID(ctx, NewID())
+}
+
+// validID accepts non-empty, bounded ids made of printable ASCII, which keeps
+// header and log injection out of correlated output.
+func validID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
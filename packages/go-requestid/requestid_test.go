@@ -0,0 +1,125 @@
+package gorequestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/segmentio/kafka-go"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func setupRouter(seen *string) *gin.Engine {
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/api/v1/files", func(c *gin.Context) {
+		*seen = RequestID(c)
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestGeneratesIDWhenMissing(t *testing.T) {
+	var seen string
+	router := setupRouter(&seen)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/files", nil))
+
+	got := w.Header().Get(HeaderName)
+	if got == "" {
+		t.Fatal("expected a generated X-Request-ID response header")
+	}
+	if seen != got {
+		t.Errorf("expected handler to see %q, got %q", got, seen)
+	}
+
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, httptest.NewRequest("GET", "/api/v1/files", nil))
+	if w2.Header().Get(HeaderName) == got {
+		t.Error("expected a distinct id per request")
+	}
+}
+
+func TestPassesThroughIncomingID(t *testing.T) {
+	var seen string
+	router := setupRouter(&seen)
+
+	req := httptest.NewRequest("GET", "/api/v1/files", nil)
+	req.Header.Set(HeaderName, "upstream-abc-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get(HeaderName); got != "upstream-abc-123" {
+		t.Errorf("expected incoming id to be echoed, got %q", got)
+	}
+	if seen != "upstream-abc-123" {
+		t.Errorf("expected handler to see incoming id, got %q", seen)
+	}
+}
+
+func TestRejectsMalformedIncomingID(t *testing.T) {
+	var seen string
+	router := setupRouter(&seen)
+
+	for _, bad := range []string{"has space", strings.Repeat("a", maxIDLength+1)} {
+		req := httptest.NewRequest("GET", "/api/v1/files", nil)
+		req.Header.Set(HeaderName, bad)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if got := w.Header().Get(HeaderName); got == bad || got == "" {
+			t.Errorf("expected malformed id %q to be replaced, got %q", bad, got)
+		}
+	}
+}
+
+func TestContextRetrieval(t *testing.T) {
+	router := gin.New()
+	router.Use(Middleware())
+
+	var fromCtx string
+	var fields map[string]interface{}
+	router.GET("/api/v1/files", func(c *gin.Context) {
+		fromCtx = FromContext(c.Request.Context())
+		fields = LogrusFields(c)
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/files", nil)
+	req.Header.Set(HeaderName, "req-42")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if fromCtx != "req-42" {
+		t.Errorf("expected request context to carry id, got %q", fromCtx)
+	}
+	if fields[FieldName] != "req-42" {
+		t.Errorf("expected log field %s=req-42, got %v", FieldName, fields)
+	}
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("expected empty id for bare context, got %q", got)
+	}
+}
+
+func TestKafkaRoundTrip(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-99")
+	header := KafkaHeader(ctx)
+	if header.Key != HeaderName || string(header.Value) != "req-99" {
+		t.Fatalf("unexpected header: %+v", header)
+	}
+
+	consumed := ContextFromKafka(context.Background(), []kafka.Header{{Key: "other", Value: []byte("x")}, header})
+	if got := FromContext(consumed); got != "req-99" {
+		t.Errorf("expected consumer context to carry req-99, got %q", got)
+	}
+
+	fresh := ContextFromKafka(context.Background(), nil)
+	if FromContext(fresh) == "" {
+		t.Error("expected a generated id for messages without the header")
+	}
+}
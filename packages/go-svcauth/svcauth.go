@@ -0,0 +1,313 @@
+// Package gosvcauth signs and verifies internal service-to-service requests.
+//
+// A calling service signs each request with a shared HMAC secret. The
+// signature covers the method, path, caller name, audience (the receiving
+// service), timestamp, a random nonce and a hash of the body. The receiving
+// service checks the signature and audience, rejects timestamps outside the
+// allowed clock skew and rejects nonces it has already seen.
+//
+// Use a separate secret for each caller and receiver pair. Every receiver
+// holds the secrets of its callers, so a secret shared with several
+// receivers lets any of them sign requests to the others as that caller.
+// The audience stops a captured request being replayed to another service,
+// and replicas of one service should share a NonceStore to stop replays
+// across instances.
+//
+// Usage:
+//
+//	// caller
+//	client := &http.Client{Transport: gosvcauth.NewTransport(gosvcauth.Signer{
+//		Service:  "file-service",
+//		Audience: "media-service",
+//		Secret:   []byte(os.Getenv("SVC_AUTH_MEDIA_SECRET")),
+//	}, nil)}
+//
+//	// receiver
+//	verifier := gosvcauth.NewVerifier(map[string][]byte{
+//		"file-service": []byte(os.Getenv("SVC_AUTH_FILE_SECRET")),
+//	})
+//	verifier.Audience = "media-service"
+//	internal := router.Group("/internal", gosvcauth.Middleware(verifier))
+package gosvcauth
+
+import (
+	"bytes"
+	"container/heap"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Header names carried on signed requests.
+const (
+	HeaderService   = "X-Service-Name"
+	HeaderAudience  = "X-Service-Audience"
+	HeaderTimestamp = "X-Service-Timestamp"
+	HeaderNonce     = "X-Service-Nonce"
+	HeaderSignature = "X-Service-Signature"
+)
+
+// DefaultMaxSkew is how far a request timestamp may differ from the
+// verifier's clock.
+const DefaultMaxSkew = 30 * time.Second
+
+// DefaultMaxBodyBytes bounds how much of an unauthenticated body Verify reads.
+const DefaultMaxBodyBytes = 1 << 20
+
+var (
+	ErrMissingHeaders = errors.New("missing service auth headers")
+	ErrUnknownService = errors.New("unknown calling service")
+	ErrWrongAudience  = errors.New("service auth audience does not match this service")
+	ErrExpired        = errors.New("service auth timestamp outside allowed skew")
+	ErrBadSignature   = errors.New("invalid service auth signature")
+	ErrReplayed       = errors.New("service auth nonce already used")
+	ErrBodyTooLarge   = errors.New("request body too large to verify")
+)
+
+// Signer adds auth headers to outgoing requests.
+type Signer struct {
+	Service string
+	Secret  []byte
+
+	// Audience names the receiving service. Defaults to the request host.
+	Audience string
+
+	// Now overrides the clock, for tests.
+	Now func() time.Time
+}
+
+// Sign sets the auth headers on req. The body, if any, is read and replaced
+// so it can still be sent.
+func (s Signer) Sign(req *http.Request) error {
+	body, err := readBody(req, 0)
+	if err != nil {
+		return err
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	ts := strconv.FormatInt(now().Unix(), 10)
+	audience := s.Audience
+	if audience == "" {
+		audience = requestHost(req)
+	}
+
+	req.Header.Set(HeaderService, s.Service)
+	req.Header.Set(HeaderAudience, audience)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, signature(s.Secret, req.Method, req.URL.RequestURI(), s.Service, audience, ts, nonce, body))
+	return nil
+}
+
+// NonceStore remembers nonces until they expire. Implementations must be
+// safe for concurrent use.
+type NonceStore interface {
+	// Seen records nonce and reports whether it was already recorded.
+	Seen(nonce string, expires time.Time) bool
+}
+
+// Verifier checks signed requests.
+type Verifier struct {
+	// Keys maps calling service names to their shared secrets.
+	Keys    map[string][]byte
+	MaxSkew time.Duration
+	Nonces  NonceStore
+
+	// Audience is this service's name as callers set it in Signer.Audience.
+	// When empty, the signed audience must equal the request's Host.
+	Audience string
+
+	// MaxBodyBytes bounds the body read before the signature is checked.
+	// Larger bodies are rejected with ErrBodyTooLarge.
+	MaxBodyBytes int64
+
+	// Now overrides the clock, for tests.
+	Now func() time.Time
+}
+
+// NewVerifier creates a Verifier with the default skew and an in-memory
+// nonce store.
+func NewVerifier(keys map[string][]byte) *Verifier {
+	return &Verifier{
+		Keys:         keys,
+		MaxSkew:      DefaultMaxSkew,
+		Nonces:       NewMemoryNonceStore(),
+		MaxBodyBytes: DefaultMaxBodyBytes,
+	}
+}
+
+// Verify checks the auth headers on req and returns the calling service.
+// The body, if any, is read and replaced so handlers can still read it.
+func (v *Verifier) Verify(req *http.Request) (string, error) {
+	service := req.Header.Get(HeaderService)
+	audience := req.Header.Get(HeaderAudience)
+	ts := req.Header.Get(HeaderTimestamp)
+	nonce := req.Header.Get(HeaderNonce)
+	sig := req.Header.Get(HeaderSignature)
+	if service == "" || audience == "" || ts == "" || nonce == "" || sig == "" {
+		return "", ErrMissingHeaders
+	}
+	want := v.Audience
+	if want == "" {
+		want = requestHost(req)
+	}
+	if audience != want {
+		return "", ErrWrongAudience
+	}
+
+	secret, ok := v.Keys[service]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: bad timestamp", ErrExpired)
+	}
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	skew := v.MaxSkew
+	if skew <= 0 {
+		skew = DefaultMaxSkew
+	}
+	sent := time.Unix(unix, 0)
+	if d := now().Sub(sent); d > skew || d < -skew {
+		return "", ErrExpired
+	}
+
+	maxBody := v.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = DefaultMaxBodyBytes
+	}
+	body, err := readBody(req, maxBody)
+	if err != nil {
+		return "", err
+	}
+	expected := signature(secret, req.Method, req.URL.RequestURI(), service, audience, ts, nonce, body)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return "", ErrBadSignature
+	}
+
+	// Only checked once the signature is valid, so forged requests cannot
+	// burn nonces.
+	if v.Nonces != nil && v.Nonces.Seen(service+":"+nonce, sent.Add(skew)) {
+		return "", ErrReplayed
+	}
+	return service, nil
+}
+
+// MemoryNonceStore is an in-process NonceStore. Services running several
+// replicas should share a store, e.g. backed by Redis, to catch replays
+// across instances.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	expiry nonceHeap
+	now    func() time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[string]time.Time), now: time.Now}
+}
+
+func (s *MemoryNonceStore) Seen(nonce string, expires time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Evict in expiry order; each nonce is popped once, so this is
+	// O(log n) amortised per call.
+	now := s.now()
+	for len(s.expiry) > 0 && now.After(s.expiry[0].expires) {
+		delete(s.nonces, heap.Pop(&s.expiry).(nonceEntry).nonce)
+	}
+
+	if _, ok := s.nonces[nonce]; ok {
+		return true
+	}
+	s.nonces[nonce] = expires
+	heap.Push(&s.expiry, nonceEntry{nonce: nonce, expires: expires})
+	return false
+}
+
+type nonceEntry struct {
+	nonce   string
+	expires time.Time
+}
+
+// nonceHeap is a min-heap of nonces ordered by expiry.
+type nonceHeap []nonceEntry
+
+func (h nonceHeap) Len() int            { return len(h) }
+func (h nonceHeap) Less(i, j int) bool  { return h[i].expires.Before(h[j].expires) }
+func (h nonceHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nonceHeap) Push(x interface{}) { *h = append(*h, x.(nonceEntry)) }
+func (h *nonceHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+func signature(secret []byte, method, uri, service, audience, ts, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%s\n%s\n%x", method, uri, service, audience, ts, nonce, bodyHash)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestHost is the host a request is addressed to, on either side.
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// readBody reads and replaces req.Body. A positive max rejects bodies
+// longer than max bytes without reading past max+1.
+func readBody(req *http.Request, max int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if max > 0 && req.ContentLength > max {
+		return nil, ErrBodyTooLarge
+	}
+	var r io.Reader = req.Body
+	if max > 0 {
+		r = io.LimitReader(req.Body, max+1)
+	}
+	body, err := io.ReadAll(r)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if max > 0 && int64(len(body)) > max {
+		return nil, ErrBodyTooLarge
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
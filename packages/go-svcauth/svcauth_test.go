@@ -0,0 +1,203 @@
+package gosvcauth
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+var secret = []byte("test-shared-secret")
+
+func newServer(t *testing.T, v *Verifier) *httptest.Server {
+	t.Helper()
+	r := gin.New()
+	r.POST("/internal/purge", Middleware(v), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"caller": CallerService(c), "body": string(body)}})
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestValidSignedRequest(t *testing.T) {
+	srv := newServer(t, NewVerifier(map[string][]byte{"file-service": secret}))
+	client := &http.Client{Transport: NewTransport(Signer{Service: "file-service", Secret: secret}, nil)}
+
+	resp, err := client.Post(srv.URL+"/internal/purge?path=a.png", "application/json", strings.NewReader(`{"key":"a.png"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if !strings.Contains(string(body), `"caller":"file-service"`) || !strings.Contains(string(body), `a.png`) {
+		t.Errorf("expected caller and body passed through, got %s", body)
+	}
+}
+
+func TestExpiredRequestRejected(t *testing.T) {
+	srv := newServer(t, NewVerifier(map[string][]byte{"file-service": secret}))
+	stale := func() time.Time { return time.Now().Add(-2 * DefaultMaxSkew) }
+	client := &http.Client{Transport: NewTransport(Signer{Service: "file-service", Secret: secret, Now: stale}, nil)}
+
+	resp, err := client.Post(srv.URL+"/internal/purge", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an expired request, got %d", resp.StatusCode)
+	}
+}
+
+func signedRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/internal/purge", strings.NewReader(body))
+	if err := (Signer{Service: "file-service", Secret: secret}).Sign(req); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	return req
+}
+
+func TestTamperedRequestRejected(t *testing.T) {
+	v := NewVerifier(map[string][]byte{"file-service": secret, "notification-service": secret})
+
+	tamperedBody := signedRequest(t, `{"key":"a.png"}`)
+	tamperedBody.Body = io.NopCloser(strings.NewReader(`{"key":"b.png"}`))
+	if _, err := v.Verify(tamperedBody); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for a modified body, got %v", err)
+	}
+
+	tamperedCaller := signedRequest(t, `{}`)
+	tamperedCaller.Header.Set(HeaderService, "notification-service")
+	if _, err := v.Verify(tamperedCaller); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for a changed caller, got %v", err)
+	}
+
+	wrongKey := httptest.NewRequest(http.MethodPost, "/internal/purge", nil)
+	if err := (Signer{Service: "file-service", Secret: []byte("other")}).Sign(wrongKey); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	if _, err := v.Verify(wrongKey); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for the wrong secret, got %v", err)
+	}
+}
+
+func TestReplayRejected(t *testing.T) {
+	v := NewVerifier(map[string][]byte{"file-service": secret})
+	req := signedRequest(t, `{}`)
+
+	if _, err := v.Verify(req); err != nil {
+		t.Fatalf("first use should verify, got %v", err)
+	}
+	if _, err := v.Verify(req); !errors.Is(err, ErrReplayed) {
+		t.Errorf("expected ErrReplayed on second use, got %v", err)
+	}
+}
+
+func TestMissingAndUnknown(t *testing.T) {
+	v := NewVerifier(map[string][]byte{"file-service": secret})
+
+	if _, err := v.Verify(httptest.NewRequest(http.MethodGet, "/", nil)); !errors.Is(err, ErrMissingHeaders) {
+		t.Errorf("expected ErrMissingHeaders, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := (Signer{Service: "cdn-service", Secret: secret}).Sign(req); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	if _, err := v.Verify(req); !errors.Is(err, ErrUnknownService) {
+		t.Errorf("expected ErrUnknownService, got %v", err)
+	}
+}
+
+func TestAudienceBindsRequestToReceiver(t *testing.T) {
+	keys := map[string][]byte{"file-service": secret}
+	media := NewVerifier(keys)
+	media.Audience = "media-service"
+	search := NewVerifier(keys)
+	search.Audience = "search-service"
+
+	req := httptest.NewRequest(http.MethodPost, "/internal/purge", strings.NewReader(`{}`))
+	if err := (Signer{Service: "file-service", Secret: secret, Audience: "media-service"}).Sign(req); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	if _, err := search.Verify(req); !errors.Is(err, ErrWrongAudience) {
+		t.Errorf("expected ErrWrongAudience when replayed to another service, got %v", err)
+	}
+	if _, err := media.Verify(req); err != nil {
+		t.Errorf("expected the intended receiver to verify, got %v", err)
+	}
+
+	// Rewriting the audience header breaks the signature.
+	retargeted := httptest.NewRequest(http.MethodPost, "/internal/purge", strings.NewReader(`{}`))
+	if err := (Signer{Service: "file-service", Secret: secret, Audience: "media-service"}).Sign(retargeted); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	retargeted.Header.Set(HeaderAudience, "search-service")
+	if _, err := search.Verify(retargeted); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for a rewritten audience, got %v", err)
+	}
+
+	// Without a configured audience the Host header is checked.
+	byHost := NewVerifier(keys)
+	other := signedRequest(t, `{}`)
+	other.Host = "search.internal"
+	if _, err := byHost.Verify(other); !errors.Is(err, ErrWrongAudience) {
+		t.Errorf("expected ErrWrongAudience for another host, got %v", err)
+	}
+}
+
+func TestMemoryNonceStoreEvictsExpired(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryNonceStore()
+	store.now = func() time.Time { return now }
+
+	store.Seen("a", now.Add(time.Second))
+	store.Seen("b", now.Add(time.Minute))
+	if !store.Seen("a", now.Add(time.Second)) {
+		t.Fatal("expected a repeated nonce to be reported")
+	}
+
+	now = now.Add(2 * time.Second)
+	store.Seen("c", now.Add(time.Minute))
+	if _, ok := store.nonces["a"]; ok {
+		t.Error("expected the expired nonce to be evicted")
+	}
+	if len(store.nonces) != 2 || len(store.expiry) != 2 {
+		t.Errorf("expected b and c to remain, got %d nonces and %d heap entries", len(store.nonces), len(store.expiry))
+	}
+}
+
+func TestOversizedBodyRejected(t *testing.T) {
+	v := NewVerifier(map[string][]byte{"file-service": secret})
+	v.MaxBodyBytes = 16
+
+	req := signedRequest(t, strings.Repeat("x", 64))
+	req.ContentLength = -1
+	if _, err := v.Verify(req); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("expected ErrBodyTooLarge, got %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/internal/purge", Middleware(v), func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, signedRequest(t, strings.Repeat("x", 64)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
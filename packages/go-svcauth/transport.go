@@ -0,0 +1,59 @@
+package gosvcauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContextKeyService is the gin context key holding the verified caller.
+const ContextKeyService = "callerService"
+
+// Transport is an http.RoundTripper that signs every request.
+type Transport struct {
+	Signer Signer
+	Base   http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport if nil.
+func NewTransport(signer Signer, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Signer: signer, Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request.
+	signed := req.Clone(req.Context())
+	if err := t.Signer.Sign(signed); err != nil {
+		return nil, err
+	}
+	return t.Base.RoundTrip(signed)
+}
+
+// Middleware rejects requests that fail verification with 401, or 413 when
+// the body is over the verifier's limit, and stores the calling service under
+// ContextKeyService.
+func Middleware(v *Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		service, err := v.Verify(c.Request)
+		if errors.Is(err, ErrBodyTooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(ContextKeyService, service)
+		c.Next()
+	}
+}
+
+// CallerService returns the verified calling service, or "" if the request
+// did not pass through Middleware.
+func CallerService(c *gin.Context) string {
+	return c.GetString(ContextKeyService)
+}
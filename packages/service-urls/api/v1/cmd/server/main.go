@@ -10,8 +10,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	goauth "github.com/quckapp/go-auth"
+	gocors "github.com/quckapp/go-cors"
+	godbhealth "github.com/quckapp/go-dbhealth"
+	gorequestid "github.com/quckapp/go-requestid"
 	"github.com/quckapp/service-urls-api/internal/config"
 	"github.com/quckapp/service-urls-api/internal/handler"
+	"github.com/quckapp/service-urls-api/internal/metrics"
 	"github.com/quckapp/service-urls-api/internal/middleware"
 	"github.com/quckapp/service-urls-api/internal/model"
 	"github.com/quckapp/service-urls-api/internal/repository"
@@ -44,12 +48,16 @@ func main() {
 		&model.GlobalVersionConfig{},
 		&model.VersionProfile{},
 		&model.VersionProfileEntry{},
+		&model.EnvironmentLock{},
+		&model.PromotionAudit{},
+		&model.MaintenanceMode{},
 	); err != nil {
 		logger.Fatalf("Failed to migrate database: %v", err)
 	}
 	logger.Info("Database migrated")
 
 	seedDefaultApiKey(db, logger)
+	repairGlobalApiKeys(db, logger)
 
 	serviceUrlRepo := repository.NewServiceUrlRepository(db)
 	infraRepo := repository.NewInfrastructureRepository(db)
@@ -58,6 +66,8 @@ func main() {
 	configEntryRepo := repository.NewConfigEntryRepository(db)
 	versionRepo := repository.NewVersionRepository(db)
 	versionProfileRepo := repository.NewVersionProfileRepository(db)
+	lockRepo := repository.NewEnvironmentLockRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
 
 	configSvc := service.NewConfigService(serviceUrlRepo, infraRepo, firebaseRepo, configEntryRepo)
 	serviceUrlSvc := service.NewServiceUrlService(serviceUrlRepo)
@@ -67,27 +77,72 @@ func main() {
 	versionSvc := service.NewVersionService(versionRepo, versionProfileRepo)
 	versionProfileSvc := service.NewVersionProfileService(versionProfileRepo, versionRepo)
 	authSvc := service.NewAuthService(cfg.JWTSecret)
+	apiKeySvc := service.NewApiKeyService(apiKeyRepo)
+	importSvc := service.NewImportService(db)
+	exportSvc := service.NewExportService(serviceUrlRepo, infraRepo, firebaseRepo, configEntryRepo)
+	promoteSvc := service.NewPromoteService(db)
+	keyLintSvc := service.NewKeyLintService(serviceUrlRepo, infraRepo, configEntryRepo)
+	lockSvc := service.NewEnvironmentLockService(lockRepo)
+	maintenanceSvc := service.NewMaintenanceService(maintenanceRepo, cfg.MaintenanceCacheTTL)
+	summarySvc := service.NewSummaryService(serviceUrlRepo, infraRepo, firebaseRepo, configEntryRepo, cfg.SummaryCacheTTL)
+
+	// MAINTENANCE_MODE switches maintenance on for every instance; booting
+	// without it leaves the shared state as it is.
+	if cfg.MaintenanceMode {
+		if _, err := maintenanceSvc.Enable("config", cfg.MaintenanceMessage); err != nil {
+			logger.Fatalf("Failed to enable maintenance mode: %v", err)
+		}
+	}
 
 	configHandler := handler.NewConfigHandler(configSvc)
-	adminHandler := handler.NewAdminHandler(serviceUrlSvc, infraSvc, firebaseSvc, configSvc, configEntrySvc, versionSvc, versionProfileSvc)
+	adminHandler := handler.NewAdminHandler(serviceUrlSvc, infraSvc, firebaseSvc, configSvc, configEntrySvc, versionSvc, versionProfileSvc, summarySvc, importSvc, lockSvc)
 	authHandler := handler.NewAuthHandler(authSvc)
+	apiKeyHandler := handler.NewApiKeyHandler(apiKeySvc)
+	lockHandler := handler.NewEnvironmentLockHandler(lockSvc)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceSvc)
+	exportHandler := handler.NewExportHandler(exportSvc, importSvc, logger)
+	promoteHandler := handler.NewPromoteHandler(promoteSvc)
+	keyLintHandler := handler.NewKeyLintHandler(keyLintSvc)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatalf("Failed to get database handle: %v", err)
+	}
+	dbHealth := godbhealth.New(sqlDB, godbhealth.Config{
+		Timeout: 2 * time.Second,
+		OnChange: func(ready bool, err error) {
+			if ready {
+				logger.Info("Database is reachable again")
+				return
+			}
+			logger.WithError(err).Warn("Database ping failed; reporting not ready")
+		},
+	})
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	dbHealth.Start(healthCtx)
+	healthHandler := handler.NewHealthHandler(dbHealth)
+	appMetrics := metrics.New()
 
 	router := gin.New()
 	router.Use(gin.Recovery())
-	router.Use(middleware.CORS())
-	router.Use(goauth.Logger(logger))
-	router.Use(goauth.RequestID())
+	router.Use(gocors.Middleware(gocors.Config{AllowedOrigins: cfg.AllowedOrigins}))
+	router.Use(gorequestid.Middleware())
+	router.Use(middleware.AccessLog(logger))
+	router.Use(appMetrics.Middleware())
 
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "healthy", "service": "service-urls-api"})
-	})
+	router.GET("/health", healthHandler.Health)
+	router.GET("/ready", healthHandler.Ready)
+	router.GET("/metrics", appMetrics.Handler())
 
 	configGroup := router.Group("/api/v1/config")
-	configGroup.Use(middleware.ApiKeyAuth(apiKeyRepo))
+	configGroup.Use(middleware.ApiKeyAuth(apiKeyRepo, appMetrics.RecordApiKey))
+	configGroup.Use(appMetrics.CountConfigFetches())
 	{
 		configGroup.GET("/:env/env-file", configHandler.GetEnvFile)
 		configGroup.GET("/:env/json", configHandler.GetJSON)
 		configGroup.GET("/:env/service/:key", configHandler.GetSingleValue)
+		configGroup.GET("/:env/key/:key", configHandler.LookupKey)
 		configGroup.GET("/:env/docker-compose", configHandler.GetDockerCompose)
 	}
 
@@ -97,15 +152,49 @@ func main() {
 	}
 
 	authCfg := goauth.DefaultConfig(cfg.JWTSecret)
+
+	// Registered outside adminGroup so maintenance mode can always be switched off.
+	maintenance := router.Group("/api/v1/admin/maintenance")
+	maintenance.Use(goauth.Auth(authCfg))
+	{
+		maintenance.GET("", maintenanceHandler.Get)
+		maintenance.PUT("", middleware.RequireRole(cfg.JWTSecret, cfg.MaintenanceRole), maintenanceHandler.Set)
+	}
+
 	adminGroup := router.Group("/api/v1/admin")
 	adminGroup.Use(goauth.Auth(authCfg))
+	adminGroup.Use(middleware.Maintenance(maintenanceSvc.Active))
+	adminGroup.Use(middleware.InvalidateOnWrite(summarySvc.Invalidate))
 	{
 		adminGroup.GET("/profile", authHandler.GetProfile)
 
+		keys := adminGroup.Group("/api-keys")
+		{
+			keys.GET("", apiKeyHandler.List)
+			keys.POST("", apiKeyHandler.Create)
+			keys.GET("/stale", apiKeyHandler.ListStale)
+			keys.GET("/expiring", apiKeyHandler.ListExpiring)
+			keys.POST("/:id/rotate", apiKeyHandler.Rotate)
+		}
+
+		locks := adminGroup.Group("/environments")
+		{
+			locks.GET("/locks", lockHandler.List)
+			locks.GET("/:env/lock", lockHandler.Get)
+			locks.POST("/:env/lock", middleware.RequireRole(cfg.JWTSecret, cfg.EnvLockRole), lockHandler.Lock)
+			locks.POST("/:env/unlock", middleware.RequireRole(cfg.JWTSecret, cfg.EnvLockRole), lockHandler.Unlock)
+		}
+
 		su := adminGroup.Group("/service-urls")
+		// Import validation is a dry run, so it may preview a locked environment.
+		su.Use(middleware.EnvLock(lockSvc.Get, su.BasePath()+"/:env/import/validate"))
 		{
 			su.GET("/summary", adminHandler.GetSummaries)
 			su.POST("/clone", adminHandler.Clone)
+			su.POST("/promote", promoteHandler.Promote)
+			su.GET("/export/all", middleware.RequireRoleWhen(handler.IncludeSecrets, cfg.JWTSecret, cfg.SecretsExportRole), exportHandler.ExportAll)
+			su.POST("/import/all/validate", exportHandler.ValidateImportAll)
+			su.POST("/import/all", middleware.RequireRole(cfg.JWTSecret, cfg.SecretsExportRole), exportHandler.ImportAll)
 
 			env := su.Group("/:env")
 			{
@@ -127,8 +216,10 @@ func main() {
 				env.PUT("/config-entries/:configKey", adminHandler.UpdateConfigEntry)
 				env.DELETE("/config-entries/:configKey", adminHandler.DeleteConfigEntry)
 
-				env.GET("/export", adminHandler.Export)
+				env.GET("/export", exportHandler.Export)
 				env.POST("/import", adminHandler.Import)
+				env.POST("/import/validate", adminHandler.ValidateImport)
+				env.GET("/lint", keyLintHandler.Lint)
 
 				// Version management
 				env.GET("/versions", adminHandler.ListVersions)
@@ -199,3 +290,14 @@ func seedDefaultApiKey(db *gorm.DB, logger *logrus.Logger) {
 		}
 	}
 }
+
+// repairGlobalApiKeys converts keys stored with an empty environment, which
+// FindValidKey never matched, into global keys.
+func repairGlobalApiKeys(db *gorm.DB, logger *logrus.Logger) {
+	result := db.Model(&model.ApiKey{}).Where("environment = ?", "").Update("environment", nil)
+	if result.Error != nil {
+		logger.Warnf("Failed to repair global API keys: %v", result.Error)
+	} else if result.RowsAffected > 0 {
+		logger.Infof("Converted %d API keys with an empty environment to global keys", result.RowsAffected)
+	}
+}
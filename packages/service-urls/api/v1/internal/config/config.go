@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -17,6 +20,16 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	JWTSecret  string
+
+	SummaryCacheTTL time.Duration
+	EnvLockRole     string
+	AllowedOrigins  []string
+
+	MaintenanceMode     bool
+	MaintenanceMessage  string
+	MaintenanceRole     string
+	MaintenanceCacheTTL time.Duration
+	SecretsExportRole   string
 }
 
 func Load() *Config {
@@ -28,6 +41,16 @@ func Load() *Config {
 		DBPassword: getEnv("MYSQL_PASSWORD", "root_secret"),
 		DBName:     getEnv("MYSQL_DATABASE", "quckapp_admin"),
 		JWTSecret:  getEnv("JWT_SECRET", "local-dev-jwt-secret-change-in-production-min-32-chars"),
+
+		SummaryCacheTTL: getEnvSeconds("SUMMARY_CACHE_TTL_SECONDS", 30),
+		EnvLockRole:     getEnv("ENV_LOCK_ROLE", "super_admin"),
+		AllowedOrigins:  getEnvList("ALLOWED_ORIGINS"),
+
+		MaintenanceMode:     getEnvBool("MAINTENANCE_MODE", false),
+		MaintenanceMessage:  getEnv("MAINTENANCE_MESSAGE", ""),
+		MaintenanceRole:     getEnv("MAINTENANCE_ROLE", "super_admin"),
+		MaintenanceCacheTTL: getEnvSeconds("MAINTENANCE_CACHE_TTL_SECONDS", 5),
+		SecretsExportRole:   getEnv("SECRETS_EXPORT_ROLE", "super_admin"),
 	}
 }
 
@@ -58,3 +81,32 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvSeconds(key string, defaultSeconds int) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			return time.Duration(n) * time.Second
+		}
+	}
+	return time.Duration(defaultSeconds) * time.Second
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList reads a comma-separated list, dropping blank entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	gobinderr "github.com/quckapp/go-binderr"
 	"github.com/quckapp/service-urls-api/internal/model"
 	"github.com/quckapp/service-urls-api/internal/service"
 )
@@ -23,6 +25,9 @@ type AdminHandler struct {
 	configEntrySvc    *service.ConfigEntryService
 	versionSvc        *service.VersionService
 	versionProfileSvc *service.VersionProfileService
+	summarySvc        *service.SummaryService
+	importSvc         *service.ImportService
+	lockSvc           *service.EnvironmentLockService
 }
 
 func NewAdminHandler(
@@ -33,6 +38,9 @@ func NewAdminHandler(
 	configEntrySvc *service.ConfigEntryService,
 	versionSvc *service.VersionService,
 	versionProfileSvc *service.VersionProfileService,
+	summarySvc *service.SummaryService,
+	importSvc *service.ImportService,
+	lockSvc *service.EnvironmentLockService,
 ) *AdminHandler {
 	return &AdminHandler{
 		serviceUrlSvc:     serviceUrlSvc,
@@ -42,36 +50,18 @@ func NewAdminHandler(
 		configEntrySvc:    configEntrySvc,
 		versionSvc:        versionSvc,
 		versionProfileSvc: versionProfileSvc,
+		summarySvc:        summarySvc,
+		importSvc:         importSvc,
+		lockSvc:           lockSvc,
 	}
 }
 
-type EnvironmentSummary struct {
-	Environment      string  `json:"environment"`
-	ServiceCount     int64   `json:"serviceCount"`
-	InfraCount       int64   `json:"infraCount"`
-	ConfigEntryCount int64   `json:"configEntryCount"`
-	HasFirebase      bool    `json:"hasFirebase"`
-	LastUpdated      *string `json:"lastUpdated"`
-}
-
 func (h *AdminHandler) GetSummaries(c *gin.Context) {
-	envs := []string{"local", "development", "qa", "uat1", "uat2", "uat3", "staging", "production", "live"}
-	summaries := make([]EnvironmentSummary, 0, len(envs))
-
-	for _, env := range envs {
-		svcCount, _ := h.serviceUrlSvc.CountByEnv(env)
-		infraCount, _ := h.infraSvc.CountByEnv(env)
-		configEntryCount, _ := h.configEntrySvc.CountByEnv(env)
-		hasFB, _ := h.firebaseSvc.Exists(env)
-		summaries = append(summaries, EnvironmentSummary{
-			Environment:      env,
-			ServiceCount:     svcCount,
-			InfraCount:       infraCount,
-			ConfigEntryCount: configEntryCount,
-			HasFirebase:      hasFB,
-		})
+	summaries, err := h.summarySvc.GetSummaries()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-
 	c.JSON(http.StatusOK, gin.H{"data": summaries})
 }
 
@@ -90,12 +80,12 @@ func (h *AdminHandler) CreateService(c *gin.Context) {
 	env := c.Param("env")
 	var svc model.ServiceUrl
 	if err := c.ShouldBindJSON(&svc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	svc.Environment = env
 	if err := h.serviceUrlSvc.Create(&svc); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": svc})
@@ -106,7 +96,7 @@ func (h *AdminHandler) UpdateService(c *gin.Context) {
 	key := c.Param("serviceKey")
 	var svc model.ServiceUrl
 	if err := c.ShouldBindJSON(&svc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	if err := h.serviceUrlSvc.Update(env, key, &svc); err != nil {
@@ -140,12 +130,18 @@ func (h *AdminHandler) CreateInfrastructure(c *gin.Context) {
 	env := c.Param("env")
 	var infra model.InfrastructureConfig
 	if err := c.ShouldBindJSON(&infra); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	infra.Environment = env
+	if c.Query("generateConnectionString") == "true" {
+		if err := h.infraSvc.FillConnectionString(&infra); err != nil {
+			respondServiceError(c, err)
+			return
+		}
+	}
 	if err := h.infraSvc.Create(&infra); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": infra})
@@ -156,11 +152,18 @@ func (h *AdminHandler) UpdateInfrastructure(c *gin.Context) {
 	key := c.Param("infraKey")
 	var infra model.InfrastructureConfig
 	if err := c.ShouldBindJSON(&infra); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
+	if c.Query("generateConnectionString") == "true" {
+		infra.InfraKey = key
+		if err := h.infraSvc.FillConnectionString(&infra); err != nil {
+			respondServiceError(c, err)
+			return
+		}
+	}
 	if err := h.infraSvc.Update(env, key, &infra); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": infra})
@@ -190,7 +193,7 @@ func (h *AdminHandler) UpsertFirebase(c *gin.Context) {
 	env := c.Param("env")
 	var fb model.FirebaseConfig
 	if err := c.ShouldBindJSON(&fb); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	fb.Environment = env
@@ -216,12 +219,12 @@ func (h *AdminHandler) CreateConfigEntry(c *gin.Context) {
 	env := c.Param("env")
 	var entry model.ConfigEntry
 	if err := c.ShouldBindJSON(&entry); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	entry.Environment = env
 	if err := h.configEntrySvc.Create(&entry); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": entry})
@@ -232,7 +235,7 @@ func (h *AdminHandler) UpdateConfigEntry(c *gin.Context) {
 	key := c.Param("configKey")
 	var entry model.ConfigEntry
 	if err := c.ShouldBindJSON(&entry); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	updated, err := h.configEntrySvc.Update(env, key, &entry)
@@ -254,65 +257,45 @@ func (h *AdminHandler) DeleteConfigEntry(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
-type BulkExportResponse struct {
-	Environment    string                      `json:"environment"`
-	Services       []model.ServiceUrl          `json:"services"`
-	Infrastructure []model.InfrastructureConfig `json:"infrastructure"`
-	Firebase       *model.FirebaseConfig       `json:"firebase"`
-	ConfigEntries  []model.ConfigEntry         `json:"configEntries"`
-}
-
-func (h *AdminHandler) Export(c *gin.Context) {
+func (h *AdminHandler) Import(c *gin.Context) {
 	env := c.Param("env")
-	services, _ := h.serviceUrlSvc.List(env, "")
-	infra, _ := h.infraSvc.List(env)
-	fb, _ := h.firebaseSvc.Get(env)
-	configEntries, _ := h.configEntrySvc.List(env, "")
+	var req service.BulkImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gobinderr.Respond(c, err)
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"data": BulkExportResponse{
-		Environment:    env,
-		Services:       services,
-		Infrastructure: infra,
-		Firebase:       fb,
-		ConfigEntries:  configEntries,
-	}})
-}
+	result, err := h.importSvc.Import(env, &req)
+	if errors.Is(err, service.ErrConfirmTokenMismatch) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrMaskedSecret) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
 
-type BulkImportRequest struct {
-	Services       []model.ServiceUrl          `json:"services"`
-	Infrastructure []model.InfrastructureConfig `json:"infrastructure"`
-	ConfigEntries  []model.ConfigEntry         `json:"configEntries"`
+	c.JSON(http.StatusOK, gin.H{"data": result})
 }
 
-func (h *AdminHandler) Import(c *gin.Context) {
+func (h *AdminHandler) ValidateImport(c *gin.Context) {
 	env := c.Param("env")
-	var req BulkImportRequest
+	var req service.BulkImportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 
-	created := 0
-	for i := range req.Services {
-		req.Services[i].Environment = env
-		if err := h.serviceUrlSvc.Create(&req.Services[i]); err == nil {
-			created++
-		}
-	}
-	for i := range req.Infrastructure {
-		req.Infrastructure[i].Environment = env
-		if err := h.infraSvc.Create(&req.Infrastructure[i]); err == nil {
-			created++
-		}
-	}
-	for i := range req.ConfigEntries {
-		req.ConfigEntries[i].Environment = env
-		if err := h.configEntrySvc.Create(&req.ConfigEntries[i]); err == nil {
-			created++
-		}
+	plan, err := h.importSvc.Plan(env, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{"imported": created}})
+	c.JSON(http.StatusOK, gin.H{"data": plan})
 }
 
 type CloneRequest struct {
@@ -324,7 +307,7 @@ type CloneRequest struct {
 func (h *AdminHandler) Clone(c *gin.Context) {
 	var req CloneRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 
@@ -333,6 +316,16 @@ func (h *AdminHandler) Clone(c *gin.Context) {
 		return
 	}
 
+	lock, err := h.lockSvc.Get(req.TargetEnv)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if lock != nil {
+		c.JSON(http.StatusLocked, gin.H{"error": "target environment is locked", "lockedBy": lock.LockedBy, "reason": lock.Reason})
+		return
+	}
+
 	services, _ := h.serviceUrlSvc.List(req.SourceEnv, "")
 	infra, _ := h.infraSvc.List(req.SourceEnv)
 	configEntries, _ := h.configEntrySvc.ListUnmasked(req.SourceEnv, "")
@@ -391,7 +384,7 @@ func (h *AdminHandler) CreateVersion(c *gin.Context) {
 	env := c.Param("env")
 	var vc model.VersionConfig
 	if err := c.ShouldBindJSON(&vc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	if err := h.versionSvc.Create(env, &vc); err != nil {
@@ -470,7 +463,7 @@ func (h *AdminHandler) BulkPlanVersions(c *gin.Context) {
 	env := c.Param("env")
 	var req BulkPlanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	if err := h.versionSvc.BulkPlan(env, req.ApiVersion, req.ServiceKeys, req.Changelog); err != nil {
@@ -488,7 +481,7 @@ func (h *AdminHandler) BulkActivateVersions(c *gin.Context) {
 	env := c.Param("env")
 	var req BulkActivateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	if err := h.versionSvc.BulkActivate(env, req.ApiVersion); err != nil {
@@ -512,7 +505,7 @@ func (h *AdminHandler) UpdateGlobalConfig(c *gin.Context) {
 	env := c.Param("env")
 	var gc model.GlobalVersionConfig
 	if err := c.ShouldBindJSON(&gc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 	if err := h.versionSvc.UpdateGlobalConfig(env, &gc); err != nil {
@@ -558,7 +551,7 @@ type ProfileEntryRequest struct {
 func (h *AdminHandler) CreateProfile(c *gin.Context) {
 	var req CreateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 
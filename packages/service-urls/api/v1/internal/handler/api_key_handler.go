@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gobinderr "github.com/quckapp/go-binderr"
+	gopagination "github.com/quckapp/go-pagination"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+type ApiKeyHandler struct {
+	apiKeySvc *service.ApiKeyService
+}
+
+func NewApiKeyHandler(apiKeySvc *service.ApiKeyService) *ApiKeyHandler {
+	return &ApiKeyHandler{apiKeySvc: apiKeySvc}
+}
+
+// apiKeyPagination pages the key list 50 at a time, up to 200 per request.
+var apiKeyPagination = gopagination.Config{DefaultLimit: 50, MaxLimit: 200}
+
+func (h *ApiKeyHandler) List(c *gin.Context) {
+	filter := repository.ApiKeyFilter{
+		Name:        c.Query("name"),
+		Environment: c.Query("environment"),
+	}
+	if raw := c.Query("isActive"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "isActive must be true or false"})
+			return
+		}
+		filter.IsActive = &active
+	}
+	page := gopagination.Parse(c, apiKeyPagination)
+	filter.Limit = page.Limit
+	filter.Offset = page.Offset
+
+	keys, total, err := h.apiKeySvc.List(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gopagination.NewResponse(keys, page, total))
+}
+
+func (h *ApiKeyHandler) ListStale(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+		return
+	}
+	keys, err := h.apiKeySvc.ListStale(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+type CreateApiKeyRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Environment   string `json:"environment"`
+	ExpiresInDays int    `json:"expiresInDays" binding:"min=0"`
+}
+
+func (h *ApiKeyHandler) Create(c *gin.Context) {
+	var req CreateApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gobinderr.Respond(c, err)
+		return
+	}
+	if req.Environment != "" && !validEnvironments[req.Environment] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid environment"})
+		return
+	}
+	issued, err := h.apiKeySvc.Create(req.Name, req.Environment, req.ExpiresInDays)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": issued})
+}
+
+type RotateApiKeyRequest struct {
+	GraceHours    int `json:"graceHours" binding:"min=0"`
+	ExpiresInDays int `json:"expiresInDays" binding:"min=0"`
+}
+
+func (h *ApiKeyHandler) Rotate(c *gin.Context) {
+	var req RotateApiKeyRequest
+	// The body is optional; an empty one, chunked or not, keeps the defaults.
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		gobinderr.Respond(c, err)
+		return
+	}
+	issued, err := h.apiKeySvc.Rotate(c.Param("id"), time.Duration(req.GraceHours)*time.Hour, req.ExpiresInDays)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusCreated, gin.H{"data": issued})
+	case errors.Is(err, service.ErrApiKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrApiKeyInactive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *ApiKeyHandler) ListExpiring(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "14"))
+	if err != nil || days < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+		return
+	}
+	keys, err := h.apiKeySvc.ListExpiring(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	gopagination "github.com/quckapp/go-pagination"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"github.com/quckapp/service-urls-api/internal/service"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const rotateKeyID = "6f1c7a52-8f0e-4d5e-9a51-0c1b2d3e4f50"
+
+func newApiKeyRouter(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open gorm: %v", err)
+	}
+
+	h := NewApiKeyHandler(service.NewApiKeyService(repository.NewApiKeyRepository(db)))
+	router := gin.New()
+	router.GET("/api-keys", h.List)
+	router.POST("/api-keys/:id/rotate", h.Rotate)
+	return router, mock
+}
+
+func rotate(router *gin.Engine, body string, chunked bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api-keys/"+rotateKeyID+"/rotate", strings.NewReader(body))
+	if chunked {
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRotateBindsChunkedBody(t *testing.T) {
+	router, _ := newApiKeyRouter(t)
+	if w := rotate(router, `{"graceHours":-1}`, true); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a chunked body to be validated, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRotateStatusCodes(t *testing.T) {
+	cases := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		want   int
+	}{
+		{"not found", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM `api_keys` WHERE id = \\?").WillReturnError(gorm.ErrRecordNotFound)
+		}, http.StatusNotFound},
+		{"inactive", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM `api_keys` WHERE id = \\?").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active"}).AddRow(rotateKeyID, "ci-key", false))
+		}, http.StatusConflict},
+		{"database down", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM `api_keys` WHERE id = \\?").WillReturnError(errors.New("dial tcp: connection refused"))
+		}, http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		router, mock := newApiKeyRouter(t)
+		mock.ExpectBegin()
+		tc.expect(mock)
+		mock.ExpectRollback()
+
+		if w := rotate(router, "", false); w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d: %s", tc.name, tc.want, w.Code, w.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+}
+
+func TestListPaginatesWithDefaults(t *testing.T) {
+	router, mock := newApiKeyRouter(t)
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `api_keys`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` ORDER BY created_at DESC LIMIT 50$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(rotateKeyID, "ci-key"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api-keys", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected the default limit of 50: %v", err)
+	}
+	var body gopagination.Response[map[string]interface{}]
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(body.Data) != 1 || body.Pagination.Total != 1 || body.Pagination.Limit != 50 || body.Pagination.HasMore {
+		t.Errorf("unexpected page: %+v", body)
+	}
+}
+
+func TestListCapsLimit(t *testing.T) {
+	router, mock := newApiKeyRouter(t)
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `api_keys`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(500))
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` ORDER BY created_at DESC LIMIT 200 OFFSET 10").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api-keys?limit=10000&offset=10", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected limit to be capped at 200: %v", err)
+	}
+}
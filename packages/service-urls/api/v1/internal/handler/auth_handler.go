@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	goauth "github.com/quckapp/go-auth"
+	gobinderr "github.com/quckapp/go-binderr"
 	"github.com/quckapp/service-urls-api/internal/service"
 )
 
@@ -19,7 +20,7 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req service.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		gobinderr.Respond(c, err)
 		return
 	}
 
@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/quckapp/service-urls-api/internal/service"
@@ -22,7 +27,7 @@ func (h *ConfigHandler) GetEnvFile(c *gin.Context) {
 		c.String(http.StatusInternalServerError, "failed to load config: %s", err.Error())
 		return
 	}
-	c.String(http.StatusOK, service.FormatEnvFile(config))
+	respondWithETag(c, "text/plain; charset=utf-8", []byte(service.FormatEnvFile(config)))
 }
 
 func (h *ConfigHandler) GetJSON(c *gin.Context) {
@@ -32,7 +37,12 @@ func (h *ConfigHandler) GetJSON(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, config)
+	body, err := json.Marshal(config)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithETag(c, "application/json; charset=utf-8", body)
 }
 
 func (h *ConfigHandler) GetSingleValue(c *gin.Context) {
@@ -46,6 +56,22 @@ func (h *ConfigHandler) GetSingleValue(c *gin.Context) {
 	c.String(http.StatusOK, val)
 }
 
+func (h *ConfigHandler) LookupKey(c *gin.Context) {
+	env := c.Param("env")
+	key := c.Param("key")
+	result, err := h.configService.LookupKey(env, key, c.Query("source"))
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, result)
+	case errors.Is(err, service.ErrInvalidSource):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *ConfigHandler) GetDockerCompose(c *gin.Context) {
 	env := c.Param("env")
 	config, err := h.configService.GetFlatConfig(env)
@@ -53,5 +79,29 @@ func (h *ConfigHandler) GetDockerCompose(c *gin.Context) {
 		c.String(http.StatusInternalServerError, "failed to load config: %s", err.Error())
 		return
 	}
-	c.String(http.StatusOK, service.FormatDockerCompose(config))
+	respondWithETag(c, "text/plain; charset=utf-8", []byte(service.FormatDockerCompose(config)))
+}
+
+// respondWithETag writes body with a content-hash ETag, or 304 Not Modified
+// when the request's If-None-Match already names that ETag.
+func respondWithETag(c *gin.Context, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := fmt.Sprintf("\"%x\"", sum[:16])
+	c.Header("ETag", etag)
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+func serveEnvFile(config map[string]string, ifNoneMatch string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.GET("/env-file", func(c *gin.Context) {
+		respondWithETag(c, "text/plain; charset=utf-8", []byte(service.FormatEnvFile(config)))
+	})
+	req := httptest.NewRequest(http.MethodGet, "/env-file", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestETagStableForUnchangedConfig(t *testing.T) {
+	config := map[string]string{"A_URL": "http://a", "B_URL": "http://b", "C_URL": "http://c", "D_URL": "http://d"}
+
+	first := serveEnvFile(config, "").Header().Get("ETag")
+	if first == "" {
+		t.Fatal("expected an ETag header")
+	}
+	for i := 0; i < 20; i++ {
+		if got := serveEnvFile(config, "").Header().Get("ETag"); got != first {
+			t.Fatalf("ETag changed for unchanged config: %s != %s", got, first)
+		}
+	}
+
+	config["B_URL"] = "http://b2"
+	if got := serveEnvFile(config, "").Header().Get("ETag"); got == first {
+		t.Error("expected ETag to change when config changes")
+	}
+}
+
+func TestETagNotModified(t *testing.T) {
+	config := map[string]string{"A_URL": "http://a"}
+	etag := serveEnvFile(config, "").Header().Get("ETag")
+
+	w := serveEnvFile(config, etag)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected empty body on 304, got %q", w.Body.String())
+	}
+
+	w = serveEnvFile(config, `"stale", `+etag)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 when ETag is one of several candidates, got %d", w.Code)
+	}
+
+	w = serveEnvFile(config, `"stale"`)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 for non-matching ETag, got %d", w.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	goauth "github.com/quckapp/go-auth"
+	gobinderr "github.com/quckapp/go-binderr"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+type EnvironmentLockHandler struct {
+	lockSvc *service.EnvironmentLockService
+}
+
+func NewEnvironmentLockHandler(lockSvc *service.EnvironmentLockService) *EnvironmentLockHandler {
+	return &EnvironmentLockHandler{lockSvc: lockSvc}
+}
+
+func (h *EnvironmentLockHandler) List(c *gin.Context) {
+	locks, err := h.lockSvc.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": locks})
+}
+
+func (h *EnvironmentLockHandler) Get(c *gin.Context) {
+	lock, err := h.lockSvc.Get(c.Param("env"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": lock})
+}
+
+type LockEnvironmentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (h *EnvironmentLockHandler) Lock(c *gin.Context) {
+	env := c.Param("env")
+	if !validEnvironments[env] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid environment"})
+		return
+	}
+	var req LockEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gobinderr.Respond(c, err)
+		return
+	}
+
+	userID, _ := goauth.GetUserID(c)
+	lock, err := h.lockSvc.Lock(env, userID, req.Reason)
+	if errors.Is(err, service.ErrEnvironmentLocked) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": lock})
+}
+
+func (h *EnvironmentLockHandler) Unlock(c *gin.Context) {
+	err := h.lockSvc.Unlock(c.Param("env"))
+	if errors.Is(err, service.ErrEnvironmentNotLocked) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "unlocked"})
+}
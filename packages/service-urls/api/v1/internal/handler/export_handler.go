@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	goauth "github.com/quckapp/go-auth"
+	gobinderr "github.com/quckapp/go-binderr"
+	"github.com/quckapp/service-urls-api/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+type ExportHandler struct {
+	exportSvc *service.ExportService
+	importSvc *service.ImportService
+	logger    *logrus.Logger
+}
+
+func NewExportHandler(
+	exportSvc *service.ExportService,
+	importSvc *service.ImportService,
+	logger *logrus.Logger,
+) *ExportHandler {
+	return &ExportHandler{
+		exportSvc: exportSvc,
+		importSvc: importSvc,
+		logger:    logger,
+	}
+}
+
+// IncludeSecrets reports whether the request asks for unmasked secret values.
+func IncludeSecrets(c *gin.Context) bool {
+	return c.Query("include_secrets") == "true"
+}
+
+func (h *ExportHandler) Export(c *gin.Context) {
+	export, err := h.exportSvc.Export(c.Param("env"), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": export})
+}
+
+// ExportAll returns every environment in one response for backup and
+// disaster recovery. Unmasked secrets require include_secrets=true, which the
+// router guards with an elevated role, and every such export is logged.
+func (h *ExportHandler) ExportAll(c *gin.Context) {
+	includeSecrets := IncludeSecrets(c)
+	all, err := h.exportSvc.ExportAll(includeSecrets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if includeSecrets {
+		userID, _ := goauth.GetUserID(c)
+		h.logger.WithFields(logrus.Fields{
+			"audit":    "export_all_secrets",
+			"userId":   userID,
+			"clientIp": c.ClientIP(),
+		}).Warn("All-environment export with secrets")
+	}
+	c.JSON(http.StatusOK, gin.H{"data": all})
+}
+
+func (h *ExportHandler) ValidateImportAll(c *gin.Context) {
+	var req service.ImportAllRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gobinderr.Respond(c, err)
+		return
+	}
+
+	plan, err := h.importSvc.PlanAll(&req)
+	if err != nil {
+		h.respondImportAllError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": plan})
+}
+
+// ImportAll restores several environments. It must carry the confirm token
+// from ValidateImportAll and is refused if any target environment is locked.
+func (h *ExportHandler) ImportAll(c *gin.Context) {
+	var req service.ImportAllRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gobinderr.Respond(c, err)
+		return
+	}
+
+	created, err := h.importSvc.ImportAll(&req)
+	if err != nil {
+		h.respondImportAllError(c, err)
+		return
+	}
+
+	userID, _ := goauth.GetUserID(c)
+	h.logger.WithFields(logrus.Fields{
+		"audit":    "import_all",
+		"userId":   userID,
+		"imported": created,
+	}).Warn("All-environment import")
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"imported": created}})
+}
+
+func (h *ExportHandler) respondImportAllError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrConfirmTokenRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEnvironmentLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConfirmTokenMismatch):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnknownEnvironment), errors.Is(err, service.ErrMaskedSecret):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		respondServiceError(c, err)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Readiness is satisfied by *godbhealth.Checker.
+type Readiness interface {
+	Ready() bool
+}
+
+type HealthHandler struct {
+	db Readiness
+}
+
+func NewHealthHandler(db Readiness) *HealthHandler {
+	return &HealthHandler{db: db}
+}
+
+// Health is the liveness check; it never touches dependencies.
+func (h *HealthHandler) Health(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "healthy", "service": "service-urls-api"})
+}
+
+// Ready is the readiness check; it reports 503 while the database checker
+// reports the database down. The probe is unauthenticated, so the cause is
+// only logged, by the checker's OnChange hook.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if !h.db.Ready() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":     "unavailable",
+			"service":    "service-urls-api",
+			"components": gin.H{"database": gin.H{"status": "down"}},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":     "ready",
+		"service":    "service-urls-api",
+		"components": gin.H{"database": gin.H{"status": "up"}},
+	})
+}
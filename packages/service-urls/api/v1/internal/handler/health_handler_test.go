@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	godbhealth "github.com/quckapp/go-dbhealth"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serveReady runs one database check, as the background checker would, and
+// then probes /ready.
+func serveReady(t *testing.T, db *sql.DB) *httptest.ResponseRecorder {
+	t.Helper()
+	checker := godbhealth.New(db, godbhealth.Config{Timeout: time.Second})
+	checker.Check(context.Background())
+
+	router := gin.New()
+	h := NewHealthHandler(checker)
+	router.GET("/health", h.Health)
+	router.GET("/ready", h.Ready)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	return w
+}
+
+func TestReadyWithHealthyDatabase(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectPing()
+
+	w := serveReady(t, db)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected a database ping: %v", err)
+	}
+}
+
+func TestReadyWithClosedDatabase(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	db.Close()
+
+	w := serveReady(t, db)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var body struct {
+		Components map[string]struct {
+			Status string `json:"status"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse body: %v", err)
+	}
+	if body.Components["database"].Status != "down" {
+		t.Errorf("expected database component down, got %+v", body.Components)
+	}
+	if strings.Contains(w.Body.String(), "error") {
+		t.Errorf("expected the database error kept out of the probe response, got %s", w.Body.String())
+	}
+}
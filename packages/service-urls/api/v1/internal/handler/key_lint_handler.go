@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+type KeyLintHandler struct {
+	keyLintSvc *service.KeyLintService
+}
+
+func NewKeyLintHandler(keyLintSvc *service.KeyLintService) *KeyLintHandler {
+	return &KeyLintHandler{keyLintSvc: keyLintSvc}
+}
+
+// Lint reports keys in an environment that are not valid environment
+// variable names and would produce broken .env or compose output.
+func (h *KeyLintHandler) Lint(c *gin.Context) {
+	report, err := h.keyLintSvc.Lint(c.Param("env"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	goauth "github.com/quckapp/go-auth"
+	gobinderr "github.com/quckapp/go-binderr"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+type MaintenanceHandler struct {
+	maintenanceSvc *service.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenanceSvc *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceSvc: maintenanceSvc}
+}
+
+func (h *MaintenanceHandler) Get(c *gin.Context) {
+	status, err := h.maintenanceSvc.Status()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+type SetMaintenanceRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Message string `json:"message"`
+}
+
+func (h *MaintenanceHandler) Set(c *gin.Context) {
+	var req SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gobinderr.Respond(c, err)
+		return
+	}
+
+	var (
+		status service.MaintenanceStatus
+		err    error
+	)
+	if *req.Enabled {
+		userID, _ := goauth.GetUserID(c)
+		status, err = h.maintenanceSvc.Enable(userID, req.Message)
+	} else {
+		status, err = h.maintenanceSvc.Disable()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
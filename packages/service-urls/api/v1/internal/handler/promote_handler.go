@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	goauth "github.com/quckapp/go-auth"
+	gobinderr "github.com/quckapp/go-binderr"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+type PromoteHandler struct {
+	promoteSvc *service.PromoteService
+}
+
+func NewPromoteHandler(promoteSvc *service.PromoteService) *PromoteHandler {
+	return &PromoteHandler{promoteSvc: promoteSvc}
+}
+
+func (h *PromoteHandler) Promote(c *gin.Context) {
+	var req service.PromoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gobinderr.Respond(c, err)
+		return
+	}
+	if !validEnvironments[req.Source] || !validEnvironments[req.Target] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrUnknownEnvironment.Error()})
+		return
+	}
+
+	userID, _ := goauth.GetUserID(c)
+	result, err := h.promoteSvc.Promote(&req, userID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"data": result})
+	case errors.Is(err, service.ErrEnvironmentLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSameEnvironment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPromoteKeysNotFound):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
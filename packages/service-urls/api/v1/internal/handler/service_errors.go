@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	gobinderr "github.com/quckapp/go-binderr"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+// respondServiceError maps service-level field validation failures to the
+// same 422 shape as binding errors, and anything else to a 500.
+func respondServiceError(c *gin.Context, err error) {
+	var fe *service.InvalidFieldError
+	if errors.As(err, &fe) {
+		gobinderr.RespondFields(c, gobinderr.FieldError{
+			Field:   fe.Field,
+			Rule:    fe.Rule,
+			Message: fe.Message,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/quckapp/service-urls-api/internal/service"
+)
+
+// API key validation outcomes.
+const (
+	ApiKeyValid   = "valid"
+	ApiKeyInvalid = "invalid"
+	ApiKeyMissing = "missing"
+	ApiKeyError   = "error"
+)
+
+// Metrics owns the service's Prometheus collectors. Each instance has its own
+// registry so tests can assert on counters without global state.
+type Metrics struct {
+	registry        *prometheus.Registry
+	configFetches   *prometheus.CounterVec
+	apiKeyOutcomes  *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		configFetches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "service_urls_config_fetches_total",
+			Help: "Config API fetches by environment and output format.",
+		}, []string{"environment", "format"}),
+		apiKeyOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "service_urls_api_key_validations_total",
+			Help: "API key validation results by outcome.",
+		}, []string{"outcome"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "service_urls_http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+	m.registry.MustRegister(
+		m.configFetches,
+		m.apiKeyOutcomes,
+		m.requestDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return gin.WrapH(h)
+}
+
+// Middleware records request latency. Unmatched routes share one label so
+// scanners cannot blow up label cardinality.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.requestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// CountConfigFetches counts successful config API responses (including 304s)
+// by :env and by the path segment naming the format, e.g. "env-file".
+func (m *Metrics) CountConfigFetches() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		m.configFetches.WithLabelValues(environmentLabel(c.Param("env")), configFormat(c.FullPath())).Inc()
+	}
+}
+
+// RecordApiKey counts one API key validation outcome.
+func (m *Metrics) RecordApiKey(outcome string) {
+	m.apiKeyOutcomes.WithLabelValues(outcome).Inc()
+}
+
+// environmentLabel bounds the label to the known environments so callers
+// cannot create unlimited series through the URL.
+func environmentLabel(env string) string {
+	for _, e := range service.Environments {
+		if e == env {
+			return env
+		}
+	}
+	return "other"
+}
+
+// configFormat returns the segment after :env in a route such as
+// /api/v1/config/:env/env-file.
+func configFormat(route string) string {
+	parts := strings.Split(route, "/")
+	for i, p := range parts {
+		if p == ":env" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func setupRouter(m *Metrics) *gin.Engine {
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/metrics", m.Handler())
+
+	config := router.Group("/api/v1/config")
+	config.Use(m.CountConfigFetches())
+	config.GET("/:env/env-file", func(c *gin.Context) { c.String(http.StatusOK, "A=1\n") })
+	config.GET("/:env/json", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"A": "1"}) })
+	config.GET("/:env/service/:key", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	return router
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestConfigFetchCounterIncrements(t *testing.T) {
+	m := New()
+	router := setupRouter(m)
+
+	get(router, "/api/v1/config/qa/env-file")
+	get(router, "/api/v1/config/qa/env-file")
+	get(router, "/api/v1/config/production/json")
+	get(router, "/api/v1/config/qa/service/MISSING")
+
+	if got := testutil.ToFloat64(m.configFetches.WithLabelValues("qa", "env-file")); got != 2 {
+		t.Errorf("expected 2 qa env-file fetches, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.configFetches.WithLabelValues("production", "json")); got != 1 {
+		t.Errorf("expected 1 production json fetch, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.configFetches.WithLabelValues("qa", "service")); got != 0 {
+		t.Errorf("expected failed fetches not to be counted, got %v", got)
+	}
+}
+
+func TestConfigFetchCounterBoundsEnvironment(t *testing.T) {
+	m := New()
+	router := setupRouter(m)
+
+	get(router, "/api/v1/config/random-1/env-file")
+	get(router, "/api/v1/config/random-2/env-file")
+
+	if got := testutil.ToFloat64(m.configFetches.WithLabelValues("other", "env-file")); got != 2 {
+		t.Errorf("expected unknown environments counted as other, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.configFetches); got != 1 {
+		t.Errorf("expected a single series, got %d", got)
+	}
+}
+
+func TestMetricsEndpointExposesCollectors(t *testing.T) {
+	m := New()
+	router := setupRouter(m)
+
+	get(router, "/api/v1/config/qa/env-file")
+	m.RecordApiKey(ApiKeyInvalid)
+
+	body := get(router, "/metrics").Body.String()
+	for _, want := range []string{
+		`service_urls_config_fetches_total{environment="qa",format="env-file"} 1`,
+		`service_urls_api_key_validations_total{outcome="invalid"} 1`,
+		`service_urls_http_request_duration_seconds_count{method="GET",route="/api/v1/config/:env/env-file",status="200"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected /metrics to contain %s", want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goauth "github.com/quckapp/go-auth"
+	gorequestid "github.com/quckapp/go-requestid"
+	"github.com/sirupsen/logrus"
+)
+
+// ContextKeyApiKeyName holds the name of the API key that authenticated the request.
+const ContextKeyApiKeyName = "api_key_name"
+
+// AccessLog writes one structured line per request, including the
+// authenticated principal, so aggressive pollers can be traced to a key or user,
+// and the request ID set by gorequestid.Middleware.
+func AccessLog(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		fields := logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"route":      c.FullPath(),
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  c.ClientIP(),
+			"principal":  principal(c),
+		}
+		for k, v := range gorequestid.LogrusFields(c) {
+			fields[k] = v
+		}
+		if env := c.Param("env"); env != "" {
+			fields["environment"] = env
+		}
+
+		entry := logger.WithFields(fields)
+		switch {
+		case c.Writer.Status() >= 500:
+			entry.Error("request")
+		case c.Writer.Status() >= 400:
+			entry.Warn("request")
+		default:
+			entry.Info("request")
+		}
+	}
+}
+
+func principal(c *gin.Context) string {
+	if name := c.GetString(ContextKeyApiKeyName); name != "" {
+		return "api_key:" + name
+	}
+	if userID, ok := goauth.GetUserID(c); ok && userID != "" {
+		return "user:" + userID
+	}
+	return "anonymous"
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	gorequestid "github.com/quckapp/go-requestid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestAccessLogIncludesRequestID(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	router := gin.New()
+	router.Use(gorequestid.Middleware())
+	router.Use(AccessLog(logger))
+	router.GET("/service-urls/:env/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/service-urls/qa/services", nil)
+	req.Header.Set(gorequestid.HeaderName, "upstream-abc-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatal("expected an access log entry")
+	}
+	if entry.Level != logrus.InfoLevel {
+		t.Errorf("expected info level, got %v", entry.Level)
+	}
+	if got := entry.Data[gorequestid.FieldName]; got != "upstream-abc-123" {
+		t.Errorf("expected request_id upstream-abc-123, got %v", got)
+	}
+	if got := entry.Data["environment"]; got != "qa" {
+		t.Errorf("expected environment qa, got %v", got)
+	}
+	if w.Header().Get(gorequestid.HeaderName) != "upstream-abc-123" {
+		t.Errorf("expected the request ID echoed back, got %q", w.Header().Get(gorequestid.HeaderName))
+	}
+}
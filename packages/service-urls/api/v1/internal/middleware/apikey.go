@@ -4,28 +4,39 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/quckapp/service-urls-api/internal/metrics"
 	"github.com/quckapp/service-urls-api/internal/repository"
 )
 
-func ApiKeyAuth(repo *repository.ApiKeyRepository) gin.HandlerFunc {
+// ApiKeyAuth requires a valid X-API-Key for the request's :env. Each outcome
+// is passed to record, which may be nil.
+func ApiKeyAuth(repo *repository.ApiKeyRepository, record func(outcome string)) gin.HandlerFunc {
+	if record == nil {
+		record = func(string) {}
+	}
 	return func(c *gin.Context) {
 		key := c.GetHeader("X-API-Key")
 		if key == "" {
+			record(metrics.ApiKeyMissing)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing X-API-Key header"})
 			return
 		}
 
 		env := c.Param("env")
-		valid, err := repo.ValidateKey(key, env)
+		apiKey, err := repo.FindValidKey(key, env)
 		if err != nil {
+			record(metrics.ApiKeyError)
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to validate API key"})
 			return
 		}
-		if !valid {
+		if apiKey == nil {
+			record(metrics.ApiKeyInvalid)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
 			return
 		}
 
+		record(metrics.ApiKeyValid)
+		c.Set(ContextKeyApiKeyName, apiKey.Name)
 		c.Next()
 	}
 }
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quckapp/service-urls-api/internal/model"
+)
+
+// EnvLock rejects mutating requests against a locked :env with 423 Locked.
+// Reads pass through, as do routes without an :env parameter and the
+// readOnly routes (full route paths, e.g. dry runs that use POST).
+func EnvLock(getLock func(env string) (*model.EnvironmentLock, error), readOnly ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(readOnly))
+	for _, route := range readOnly {
+		exempt[route] = true
+	}
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if exempt[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		env := c.Param("env")
+		if env == "" {
+			c.Next()
+			return
+		}
+
+		lock, err := getLock(env)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check environment lock"})
+			return
+		}
+		if lock != nil {
+			c.AbortWithStatusJSON(http.StatusLocked, gin.H{
+				"error":    "environment is locked",
+				"lockedBy": lock.LockedBy,
+				"reason":   lock.Reason,
+				"lockedAt": lock.LockedAt,
+			})
+			return
+		}
+		c.Next()
+	}
+}
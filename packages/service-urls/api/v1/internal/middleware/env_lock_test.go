@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quckapp/service-urls-api/internal/model"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func setupLockRouter(locks map[string]*model.EnvironmentLock) *gin.Engine {
+	router := gin.New()
+	group := router.Group("/service-urls")
+	group.Use(EnvLock(func(env string) (*model.EnvironmentLock, error) {
+		return locks[env], nil
+	}, "/service-urls/:env/import/validate"))
+	group.GET("/:env/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+	group.POST("/:env/services", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	group.POST("/:env/import", func(c *gin.Context) { c.Status(http.StatusOK) })
+	group.POST("/:env/import/validate", func(c *gin.Context) { c.Status(http.StatusOK) })
+	group.POST("/clone", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func serve(router *gin.Engine, method, path string) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w.Code
+}
+
+func TestEnvLockBlocksWritesUntilUnlocked(t *testing.T) {
+	locks := map[string]*model.EnvironmentLock{
+		"production": {Environment: "production", LockedBy: "ops", Reason: "release freeze"},
+	}
+	router := setupLockRouter(locks)
+
+	if code := serve(router, http.MethodPost, "/service-urls/production/services"); code != http.StatusLocked {
+		t.Errorf("expected 423 for write to locked env, got %d", code)
+	}
+	if code := serve(router, http.MethodGet, "/service-urls/production/services"); code != http.StatusOK {
+		t.Errorf("expected reads to pass while locked, got %d", code)
+	}
+	if code := serve(router, http.MethodPost, "/service-urls/staging/services"); code != http.StatusCreated {
+		t.Errorf("expected writes to other envs to pass, got %d", code)
+	}
+	if code := serve(router, http.MethodPost, "/service-urls/production/import/validate"); code != http.StatusOK {
+		t.Errorf("expected the read-only import validation to pass while locked, got %d", code)
+	}
+	if code := serve(router, http.MethodPost, "/service-urls/production/import"); code != http.StatusLocked {
+		t.Errorf("expected import to a locked env to be rejected, got %d", code)
+	}
+	if code := serve(router, http.MethodPost, "/service-urls/clone"); code != http.StatusOK {
+		t.Errorf("expected routes without :env to pass, got %d", code)
+	}
+
+	delete(locks, "production")
+	if code := serve(router, http.MethodPost, "/service-urls/production/services"); code != http.StatusCreated {
+		t.Errorf("expected write to pass after unlock, got %d", code)
+	}
+}
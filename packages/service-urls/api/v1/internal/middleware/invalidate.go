@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidateOnWrite calls invalidate after every successful mutating request,
+// so cached read models never outlive a write made through the API.
+func InvalidateOnWrite(invalidate func()) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if c.Writer.Status() < http.StatusBadRequest {
+			invalidate()
+		}
+	}
+}
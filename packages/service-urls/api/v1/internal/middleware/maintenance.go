@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultMaintenanceMessage = "service is in maintenance mode; writes are temporarily disabled"
+
+// Maintenance rejects every mutating request with 503 while maintenance mode
+// is active. Reads, including config generation, keep working.
+func Maintenance(active func() (bool, string)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		enabled, message := active()
+		if !enabled {
+			c.Next()
+			return
+		}
+		if message == "" {
+			message = defaultMaintenanceMessage
+		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":       "maintenance mode",
+			"message":     message,
+			"maintenance": true,
+		})
+	}
+}
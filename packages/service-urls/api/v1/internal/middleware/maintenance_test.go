@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceState stands in for MaintenanceService.Active.
+type maintenanceState struct {
+	enabled bool
+	message string
+}
+
+func (m *maintenanceState) active() (bool, string) { return m.enabled, m.message }
+
+func setupMaintenanceRouter(state *maintenanceState) *gin.Engine {
+	router := gin.New()
+	router.Use(Maintenance(state.active))
+	router.GET("/config/:env/env-file", func(c *gin.Context) { c.String(http.StatusOK, "A=1\n") })
+	router.GET("/service-urls/:env/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/service-urls/:env/services", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.DELETE("/service-urls/:env/services/:key", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestMaintenanceBlocksWrites(t *testing.T) {
+	state := &maintenanceState{}
+	router := setupMaintenanceRouter(state)
+
+	if code := serve(router, http.MethodPost, "/service-urls/qa/services"); code != http.StatusCreated {
+		t.Fatalf("expected writes to pass outside maintenance, got %d", code)
+	}
+
+	*state = maintenanceState{enabled: true, message: "database upgrade"}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/service-urls/qa/services", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for write in maintenance mode, got %d", w.Code)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if body["message"] != "database upgrade" {
+		t.Errorf("expected maintenance message, got %v", body)
+	}
+
+	if code := serve(router, http.MethodDelete, "/service-urls/production/services/AUTH"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for delete in maintenance mode, got %d", code)
+	}
+
+	*state = maintenanceState{}
+	if code := serve(router, http.MethodPost, "/service-urls/qa/services"); code != http.StatusCreated {
+		t.Errorf("expected writes to resume after maintenance, got %d", code)
+	}
+}
+
+func TestMaintenanceAllowsReads(t *testing.T) {
+	router := setupMaintenanceRouter(&maintenanceState{enabled: true})
+
+	if code := serve(router, http.MethodGet, "/config/production/env-file"); code != http.StatusOK {
+		t.Errorf("expected config generation to work in maintenance mode, got %d", code)
+	}
+	if code := serve(router, http.MethodGet, "/service-urls/production/services"); code != http.StatusOK {
+		t.Errorf("expected admin reads to work in maintenance mode, got %d", code)
+	}
+	if code := serve(router, http.MethodPost, "/service-urls/production/services"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected writes to be blocked in maintenance mode, got %d", code)
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RequireRole allows the request only if its bearer token, signed with
+// jwtSecret, carries one of the given roles in its "role" claim.
+func RequireRole(jwtSecret string, roles ...string) gin.HandlerFunc {
+	return RequireRoleWhen(func(*gin.Context) bool { return true }, jwtSecret, roles...)
+}
+
+// RequireRoleWhen applies RequireRole only to requests for which cond is true,
+// e.g. when a query parameter asks for elevated output.
+func RequireRoleWhen(cond func(c *gin.Context) bool, jwtSecret string, roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		allowed[r] = true
+	}
+
+	return func(c *gin.Context) {
+		if !cond(c) {
+			c.Next()
+			return
+		}
+
+		raw := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+			return []byte(jwtSecret), nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		role, _ := claims["role"].(string)
+		if !allowed[role] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			return
+		}
+		c.Next()
+	}
+}
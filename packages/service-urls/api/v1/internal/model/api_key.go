@@ -10,12 +10,14 @@ import (
 )
 
 type ApiKey struct {
-	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	KeyHash     string    `gorm:"type:varchar(64);not null;index" json:"-"`
-	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
-	Environment string    `gorm:"type:varchar(20)" json:"environment,omitempty"`
-	IsActive    bool      `gorm:"default:true" json:"isActive"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	KeyHash     string     `gorm:"type:varchar(64);not null;index" json:"-"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	Environment *string    `gorm:"type:varchar(20)" json:"environment,omitempty"` // nil for a key valid in every environment
+	IsActive    bool       `gorm:"default:true" json:"isActive"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expiresAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
 }
 
 func (a *ApiKey) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EnvironmentLock freezes writes to one environment while it exists.
+type EnvironmentLock struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Environment string    `gorm:"type:varchar(20);uniqueIndex;not null" json:"environment"`
+	LockedBy    string    `gorm:"type:varchar(100);not null" json:"lockedBy"`
+	Reason      string    `gorm:"type:text" json:"reason"`
+	LockedAt    time.Time `gorm:"autoCreateTime" json:"lockedAt"`
+}
+
+func (l *EnvironmentLock) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// MaintenanceMode is the single row holding the global read-only switch, so
+// every instance of the service sees the same state.
+type MaintenanceMode struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Enabled   bool       `gorm:"not null" json:"enabled"`
+	Message   string     `gorm:"type:text" json:"message"`
+	EnabledBy string     `gorm:"type:varchar(100)" json:"enabledBy"`
+	Since     *time.Time `json:"since"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PromotionAudit records who promoted which keys between two environments.
+type PromotionAudit struct {
+	ID         uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	SourceEnv  string    `gorm:"type:varchar(20);not null;index" json:"sourceEnv"`
+	TargetEnv  string    `gorm:"type:varchar(20);not null;index" json:"targetEnv"`
+	Keys       string    `gorm:"type:text;not null" json:"keys"`
+	PromotedBy string    `gorm:"type:varchar(100)" json:"promotedBy"`
+	PromotedAt time.Time `gorm:"autoCreateTime" json:"promotedAt"`
+}
+
+func (a *PromotionAudit) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/quckapp/service-urls-api/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ApiKeyRepository struct {
@@ -13,11 +16,121 @@ func NewApiKeyRepository(db *gorm.DB) *ApiKeyRepository {
 	return &ApiKeyRepository{db: db}
 }
 
-func (r *ApiKeyRepository) ValidateKey(rawKey, env string) (bool, error) {
+// lastUsedResolution is how stale last_used_at may get before FindValidKey
+// refreshes it, so the config fetch path does not write on every request.
+const lastUsedResolution = time.Minute
+
+// ApiKeyFilter narrows an API key listing. Zero values mean "no filter".
+type ApiKeyFilter struct {
+	IsActive    *bool
+	Name        string
+	Environment string
+	Limit       int
+	Offset      int
+}
+
+// FindValidKey returns the active, unexpired key matching rawKey for env, or
+// nil if there is none. last_used_at is refreshed at most once per
+// lastUsedResolution.
+func (r *ApiKeyRepository) FindValidKey(rawKey, env string) (*model.ApiKey, error) {
 	hash := model.HashKey(rawKey)
-	var count int64
-	err := r.db.Model(&model.ApiKey{}).
-		Where("key_hash = ? AND is_active = ? AND (environment IS NULL OR environment = ?)", hash, true, env).
-		Count(&count).Error
-	return count > 0, err
+	var key model.ApiKey
+	err := r.db.Where("key_hash = ? AND is_active = ? AND (environment IS NULL OR environment = ?)", hash, true, env).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		First(&key).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// Best effort: a failed timestamp update must not reject a valid key.
+		if r.db.Model(&key).UpdateColumn("last_used_at", now).Error == nil {
+			key.LastUsedAt = &now
+		}
+	}
+	return &key, nil
+}
+
+// Transaction runs fn with a repository bound to a single transaction.
+func (r *ApiKeyRepository) Transaction(fn func(repo *ApiKeyRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewApiKeyRepository(tx))
+	})
+}
+
+func (r *ApiKeyRepository) Create(k *model.ApiKey) error {
+	return r.db.Create(k).Error
+}
+
+func (r *ApiKeyRepository) FindByID(id string) (*model.ApiKey, error) {
+	var result model.ApiKey
+	err := r.db.Where("id = ?", id).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindByIDForUpdate is FindByID with a row lock, for use inside a
+// transaction, so concurrent writers to the same key are serialised.
+func (r *ApiKeyRepository) FindByIDForUpdate(id string) (*model.ApiKey, error) {
+	var result model.ApiKey
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *ApiKeyRepository) SetExpiry(id string, expiresAt time.Time) error {
+	return r.db.Model(&model.ApiKey{}).Where("id = ?", id).Update("expires_at", expiresAt).Error
+}
+
+// List returns one page of keys matching the filter, and the total match count.
+func (r *ApiKeyRepository) List(f ApiKeyFilter) ([]model.ApiKey, int64, error) {
+	q := r.db.Model(&model.ApiKey{})
+	if f.IsActive != nil {
+		q = q.Where("is_active = ?", *f.IsActive)
+	}
+	if f.Name != "" {
+		q = q.Where("name LIKE ?", "%"+f.Name+"%")
+	}
+	if f.Environment != "" {
+		q = q.Where("environment = ?", f.Environment)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order("created_at DESC")
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	var results []model.ApiKey
+	err := q.Offset(f.Offset).Find(&results).Error
+	return results, total, err
+}
+
+// FindStale returns active keys that have not been used since the given time,
+// including keys that have never been used.
+func (r *ApiKeyRepository) FindStale(since time.Time) ([]model.ApiKey, error) {
+	var results []model.ApiKey
+	err := r.db.Where("is_active = ? AND (last_used_at IS NULL OR last_used_at < ?)", true, since).
+		Order("last_used_at ASC").
+		Find(&results).Error
+	return results, err
+}
+
+// FindExpiring returns active keys whose expiry falls between now and before.
+func (r *ApiKeyRepository) FindExpiring(before time.Time) ([]model.ApiKey, error) {
+	var results []model.ApiKey
+	err := r.db.Where("is_active = ? AND expires_at > ? AND expires_at <= ?", true, time.Now(), before).
+		Order("expires_at ASC").
+		Find(&results).Error
+	return results, err
 }
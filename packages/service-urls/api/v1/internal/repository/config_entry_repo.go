@@ -71,3 +71,13 @@ func (r *ConfigEntryRepository) Upsert(e *model.ConfigEntry) error {
 		DoUpdates: clause.AssignmentColumns([]string{"config_value", "category", "is_secret", "description", "is_active", "updated_by", "updated_at"}),
 	}).Create(e).Error
 }
+
+func (r *ConfigEntryRepository) AggregateByEnv() ([]EnvAggregate, error) {
+	var results []EnvAggregate
+	err := r.db.Model(&model.ConfigEntry{}).
+		Select("environment, COUNT(*) AS count, MAX(updated_at) AS last_updated").
+		Where("is_active = ?", true).
+		Group("environment").
+		Scan(&results).Error
+	return results, err
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "time"
+
+// EnvAggregate is a row count and most recent update time for one environment.
+type EnvAggregate struct {
+	Environment string
+	Count       int64
+	LastUpdated *time.Time
+}
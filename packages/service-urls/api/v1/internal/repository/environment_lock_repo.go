@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/quckapp/service-urls-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EnvironmentLockRepository struct {
+	db *gorm.DB
+}
+
+func NewEnvironmentLockRepository(db *gorm.DB) *EnvironmentLockRepository {
+	return &EnvironmentLockRepository{db: db}
+}
+
+func (r *EnvironmentLockRepository) FindByEnv(env string) (*model.EnvironmentLock, error) {
+	var result model.EnvironmentLock
+	err := r.db.Where("environment = ?", env).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindByEnvForShare is FindByEnv with a shared lock, for use inside a
+// transaction. A lock created for env by another transaction blocks until
+// the caller commits, so the environment cannot be locked mid-write.
+func (r *EnvironmentLockRepository) FindByEnvForShare(env string) (*model.EnvironmentLock, error) {
+	var result model.EnvironmentLock
+	err := r.db.Clauses(clause.Locking{Strength: "SHARE"}).Where("environment = ?", env).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *EnvironmentLockRepository) FindAll() ([]model.EnvironmentLock, error) {
+	var results []model.EnvironmentLock
+	err := r.db.Order("environment ASC").Find(&results).Error
+	return results, err
+}
+
+// Create inserts l. It returns gorm.ErrDuplicatedKey if env is already
+// locked, e.g. by a concurrent request.
+func (r *EnvironmentLockRepository) Create(l *model.EnvironmentLock) error {
+	err := r.db.Create(l).Error
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && myErr.Number == mysqlErrDuplicateEntry {
+		return gorm.ErrDuplicatedKey
+	}
+	return err
+}
+
+// mysqlErrDuplicateEntry is MySQL's ER_DUP_ENTRY.
+const mysqlErrDuplicateEntry = 1062
+
+func (r *EnvironmentLockRepository) Delete(env string) (int64, error) {
+	res := r.db.Where("environment = ?", env).Delete(&model.EnvironmentLock{})
+	return res.RowsAffected, res.Error
+}
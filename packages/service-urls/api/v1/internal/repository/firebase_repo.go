@@ -40,3 +40,12 @@ func (r *FirebaseRepository) ExistsByEnv(env string) (bool, error) {
 	err := r.db.Model(&model.FirebaseConfig{}).Where("environment = ?", env).Count(&count).Error
 	return count > 0, err
 }
+
+func (r *FirebaseRepository) AggregateByEnv() ([]EnvAggregate, error) {
+	var results []EnvAggregate
+	err := r.db.Model(&model.FirebaseConfig{}).
+		Select("environment, COUNT(*) AS count, MAX(updated_at) AS last_updated").
+		Group("environment").
+		Scan(&results).Error
+	return results, err
+}
@@ -45,3 +45,13 @@ func (r *InfrastructureRepository) CountByEnv(env string) (int64, error) {
 	err := r.db.Model(&model.InfrastructureConfig{}).Where("environment = ? AND is_active = ?", env, true).Count(&count).Error
 	return count, err
 }
+
+func (r *InfrastructureRepository) AggregateByEnv() ([]EnvAggregate, error) {
+	var results []EnvAggregate
+	err := r.db.Model(&model.InfrastructureConfig{}).
+		Select("environment, COUNT(*) AS count, MAX(updated_at) AS last_updated").
+		Where("is_active = ?", true).
+		Group("environment").
+		Scan(&results).Error
+	return results, err
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/quckapp/service-urls-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maintenanceRowID is the primary key of the only maintenance_modes row.
+const maintenanceRowID = 1
+
+type MaintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+// Get returns the maintenance state, or gorm.ErrRecordNotFound if it has
+// never been set.
+func (r *MaintenanceRepository) Get() (*model.MaintenanceMode, error) {
+	var result model.MaintenanceMode
+	err := r.db.First(&result, maintenanceRowID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Save writes m as the maintenance state, creating the row if needed.
+func (r *MaintenanceRepository) Save(m *model.MaintenanceMode) error {
+	m.ID = maintenanceRowID
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(m).Error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/quckapp/service-urls-api/internal/model"
+	"gorm.io/gorm"
+)
+
+type PromotionAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionAuditRepository(db *gorm.DB) *PromotionAuditRepository {
+	return &PromotionAuditRepository{db: db}
+}
+
+func (r *PromotionAuditRepository) Create(a *model.PromotionAudit) error {
+	return r.db.Create(a).Error
+}
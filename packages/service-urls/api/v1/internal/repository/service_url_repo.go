@@ -55,3 +55,13 @@ func (r *ServiceUrlRepository) FindAllActiveByEnv(env string) ([]model.ServiceUr
 	err := r.db.Where("environment = ? AND is_active = ?", env, true).Find(&results).Error
 	return results, err
 }
+
+func (r *ServiceUrlRepository) AggregateByEnv() ([]EnvAggregate, error) {
+	var results []EnvAggregate
+	err := r.db.Model(&model.ServiceUrl{}).
+		Select("environment, COUNT(*) AS count, MAX(updated_at) AS last_updated").
+		Where("is_active = ?", true).
+		Group("environment").
+		Scan(&results).Error
+	return results, err
+}
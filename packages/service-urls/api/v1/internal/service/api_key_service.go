@@ -0,0 +1,133 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// defaultRotationGrace is how long a rotated key keeps working so that
+// deployments can roll over to the new value.
+const defaultRotationGrace = 24 * time.Hour
+
+var (
+	ErrApiKeyNotFound = errors.New("api key not found")
+	ErrApiKeyInactive = errors.New("api key is inactive")
+)
+
+// IssuedApiKey carries a newly minted raw key. The raw value is only ever
+// returned here; the database stores its hash.
+type IssuedApiKey struct {
+	Key    string       `json:"key"`
+	ApiKey model.ApiKey `json:"apiKey"`
+}
+
+type ApiKeyService struct {
+	repo *repository.ApiKeyRepository
+}
+
+func NewApiKeyService(repo *repository.ApiKeyRepository) *ApiKeyService {
+	return &ApiKeyService{repo: repo}
+}
+
+// List returns the page of API keys selected by the filter's Limit and Offset.
+func (s *ApiKeyService) List(f repository.ApiKeyFilter) ([]model.ApiKey, int64, error) {
+	return s.repo.List(f)
+}
+
+// ListStale returns active keys not used in the last `days` days.
+func (s *ApiKeyService) ListStale(days int) ([]model.ApiKey, error) {
+	return s.repo.FindStale(time.Now().AddDate(0, 0, -days))
+}
+
+// Create issues a new key. An empty env makes a global key, valid in every
+// environment. A positive expiresInDays sets an expiry; zero means the key never expires.
+func (s *ApiKeyService) Create(name, env string, expiresInDays int) (*IssuedApiKey, error) {
+	var envPtr *string
+	if env != "" {
+		envPtr = &env
+	}
+	return issueApiKey(s.repo, name, envPtr, expiresInDays)
+}
+
+// Rotate issues a replacement for the key with the given id and schedules the
+// old key to expire after the grace period (24h when grace is zero). The
+// replacement keeps the old key's name and environment. Both writes share a
+// transaction, so a failure never leaves an issued key whose value was lost,
+// and the old row is locked so concurrent rotations of one key serialise.
+func (s *ApiKeyService) Rotate(id string, grace time.Duration, expiresInDays int) (*IssuedApiKey, error) {
+	if grace <= 0 {
+		grace = defaultRotationGrace
+	}
+
+	var issued *IssuedApiKey
+	err := s.repo.Transaction(func(repo *repository.ApiKeyRepository) error {
+		old, err := repo.FindByIDForUpdate(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", ErrApiKeyNotFound, id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load api key: %w", err)
+		}
+		if !old.IsActive {
+			return fmt.Errorf("%w: cannot rotate %s", ErrApiKeyInactive, id)
+		}
+
+		issued, err = issueApiKey(repo, old.Name, old.Environment, expiresInDays)
+		if err != nil {
+			return err
+		}
+
+		oldExpiry := time.Now().Add(grace)
+		if old.ExpiresAt == nil || old.ExpiresAt.After(oldExpiry) {
+			if err := repo.SetExpiry(id, oldExpiry); err != nil {
+				return fmt.Errorf("failed to schedule old key expiry: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
+
+// ListExpiring returns active keys that expire within the next `days` days.
+func (s *ApiKeyService) ListExpiring(days int) ([]model.ApiKey, error) {
+	return s.repo.FindExpiring(time.Now().AddDate(0, 0, days))
+}
+
+func issueApiKey(repo *repository.ApiKeyRepository, name string, env *string, expiresInDays int) (*IssuedApiKey, error) {
+	raw, err := generateRawKey()
+	if err != nil {
+		return nil, err
+	}
+	key := model.ApiKey{
+		KeyHash:     model.HashKey(raw),
+		Name:        name,
+		Environment: env,
+		IsActive:    true,
+	}
+	if expiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, expiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := repo.Create(&key); err != nil {
+		return nil, err
+	}
+	return &IssuedApiKey{Key: raw, ApiKey: key}, nil
+}
+
+func generateRawKey() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return "qk_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+func TestApiKeyListActiveOnly(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `api_keys` WHERE is_active = \\?").
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE is_active = \\? ORDER BY created_at DESC LIMIT 50").
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active"}).
+			AddRow("6f1c7a52-8f0e-4d5e-9a51-0c1b2d3e4f50", "ci-key", true))
+
+	active := true
+	keys, total, err := svc.List(repository.ApiKeyFilter{IsActive: &active, Limit: 50})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+	if total != 1 || len(keys) != 1 || keys[0].Name != "ci-key" || !keys[0].IsActive {
+		t.Errorf("unexpected result: total=%d keys=%+v", total, keys)
+	}
+}
+
+func TestApiKeyListStale(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE is_active = \\? AND \\(last_used_at IS NULL OR last_used_at < \\?\\)").
+		WithArgs(true, cutoffNear(time.Now().AddDate(0, 0, -30))).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active", "last_used_at"}).
+			AddRow("6f1c7a52-8f0e-4d5e-9a51-0c1b2d3e4f50", "never-used", true, nil))
+
+	keys, err := svc.ListStale(30)
+	if err != nil {
+		t.Fatalf("ListStale returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Name != "never-used" || keys[0].LastUsedAt != nil {
+		t.Errorf("unexpected stale keys: %+v", keys)
+	}
+}
+
+// cutoffNear matches a time argument within a second of want.
+type cutoffNear time.Time
+
+func (c cutoffNear) Match(v driver.Value) bool {
+	got, ok := v.(time.Time)
+	if !ok {
+		return false
+	}
+	d := got.Sub(time.Time(c))
+	return d > -time.Second && d < time.Second
+}
+
+func TestApiKeyCreateWithExpiry(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `api_keys`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	issued, err := svc.Create("deploy-bot", "staging", 90)
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+
+	if issued.ApiKey.ExpiresAt == nil {
+		t.Fatal("expected ExpiresAt to be set")
+	}
+	want := time.Now().AddDate(0, 0, 90)
+	if d := issued.ApiKey.ExpiresAt.Sub(want); d < -time.Minute || d > time.Minute {
+		t.Errorf("expected expiry near %s, got %s", want, issued.ApiKey.ExpiresAt)
+	}
+	if issued.ApiKey.KeyHash != model.HashKey(issued.Key) {
+		t.Error("stored hash does not match the issued key")
+	}
+}
+
+func TestApiKeyRotateIssuesDistinctKey(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	oldID := "6f1c7a52-8f0e-4d5e-9a51-0c1b2d3e4f50"
+	oldHash := model.HashKey("qk_old")
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE id = \\? .*FOR UPDATE").
+		WithArgs(oldID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key_hash", "name", "environment", "is_active"}).
+			AddRow(oldID, oldHash, "deploy-bot", "staging", true))
+	mock.ExpectExec("INSERT INTO `api_keys`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `api_keys` SET `expires_at`=\\? WHERE id = \\?").
+		WithArgs(cutoffNear(time.Now().Add(2*time.Hour)), oldID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	issued, err := svc.Rotate(oldID, 2*time.Hour, 0)
+	if err != nil {
+		t.Fatalf("Rotate returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+
+	if issued.ApiKey.KeyHash == oldHash {
+		t.Error("rotated key must differ from the old key")
+	}
+	if issued.ApiKey.ID.String() == oldID {
+		t.Error("rotated key must be a new record")
+	}
+	if issued.ApiKey.Name != "deploy-bot" || issued.ApiKey.Environment == nil || *issued.ApiKey.Environment != "staging" {
+		t.Errorf("rotated key should keep name and environment, got %+v", issued.ApiKey)
+	}
+}
+
+// globalKeyInsert expects an api_keys INSERT that binds environment as NULL,
+// which FindValidKey matches for every environment.
+func globalKeyInsert(mock sqlmock.Sqlmock, name string) {
+	mock.ExpectExec("INSERT INTO `api_keys` \\(`id`,`key_hash`,`name`,`environment`,").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), name, nil, true, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestApiKeyCreateGlobalStoresNullEnvironment(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	mock.ExpectBegin()
+	globalKeyInsert(mock, "edge-proxy")
+	mock.ExpectCommit()
+
+	issued, err := svc.Create("edge-proxy", "", 0)
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected environment stored as NULL: %v", err)
+	}
+	if issued.ApiKey.Environment != nil {
+		t.Errorf("expected a global key, got environment %q", *issued.ApiKey.Environment)
+	}
+}
+
+func TestApiKeyRotateGlobalKeyStaysGlobal(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	oldID := "6f1c7a52-8f0e-4d5e-9a51-0c1b2d3e4f50"
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE id = \\? .*FOR UPDATE").
+		WithArgs(oldID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key_hash", "name", "environment", "is_active"}).
+			AddRow(oldID, model.HashKey("qk_old"), "edge-proxy", nil, true))
+	globalKeyInsert(mock, "edge-proxy")
+	mock.ExpectExec("UPDATE `api_keys` SET `expires_at`=\\? WHERE id = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	issued, err := svc.Rotate(oldID, 0, 0)
+	if err != nil {
+		t.Fatalf("Rotate returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected the replacement stored with a NULL environment: %v", err)
+	}
+	if issued.ApiKey.Environment != nil {
+		t.Errorf("expected the replacement to stay global, got %q", *issued.ApiKey.Environment)
+	}
+}
+
+func TestApiKeyRotateRollsBackWhenExpiryFails(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	oldID := "6f1c7a52-8f0e-4d5e-9a51-0c1b2d3e4f50"
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE id = \\? .*FOR UPDATE").
+		WithArgs(oldID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active"}).AddRow(oldID, "deploy-bot", true))
+	mock.ExpectExec("INSERT INTO `api_keys`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `api_keys` SET `expires_at`=\\?").WillReturnError(errors.New("lock wait timeout"))
+	mock.ExpectRollback()
+
+	if _, err := svc.Rotate(oldID, 0, 0); err == nil {
+		t.Fatal("expected Rotate to fail")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected the new key to be rolled back: %v", err)
+	}
+}
+
+func TestFindValidKeyThrottlesLastUsedUpdate(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := repository.NewApiKeyRepository(db)
+	id := "6f1c7a52-8f0e-4d5e-9a51-0c1b2d3e4f50"
+	keyRows := func(lastUsed time.Time) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "is_active", "last_used_at"}).
+			AddRow(id, "ci-key", true, lastUsed)
+	}
+
+	updates := 0
+	db.Callback().Update().Before("gorm:update").Register("test:count_updates", func(*gorm.DB) { updates++ })
+
+	// Used seconds ago: no write on the read path.
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE").WillReturnRows(keyRows(time.Now().Add(-5 * time.Second)))
+	if key, err := repo.FindValidKey("qk_live", "qa"); err != nil || key == nil {
+		t.Fatalf("expected a valid key, got %v err=%v", key, err)
+	}
+	if updates != 0 {
+		t.Fatalf("expected no last_used_at update for a recently used key, got %d", updates)
+	}
+
+	// Used an hour ago: the timestamp is refreshed.
+	mock.ExpectQuery("SELECT \\* FROM `api_keys` WHERE").WillReturnRows(keyRows(time.Now().Add(-time.Hour)))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `api_keys` SET `last_used_at`=\\? WHERE `id` = \\?").
+		WithArgs(sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if _, err := repo.FindValidKey("qk_live", "qa"); err != nil {
+		t.Fatalf("FindValidKey returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected a stale last_used_at to be refreshed: %v", err)
+	}
+}
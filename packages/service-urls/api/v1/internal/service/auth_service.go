@@ -72,6 +72,7 @@ func (s *AuthService) generateToken(user AdminUser) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":   user.ID,
 		"email": user.PhoneNumber,
+		"role":  user.Role,
 		"iss":   "quckapp-auth",
 		"iat":   time.Now().Unix(),
 		"exp":   time.Now().Add(24 * time.Hour).Unix(),
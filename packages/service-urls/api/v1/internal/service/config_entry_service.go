@@ -36,6 +36,9 @@ func (s *ConfigEntryService) ListUnmasked(env, category string) ([]model.ConfigE
 }
 
 func (s *ConfigEntryService) Create(entry *model.ConfigEntry) error {
+	if err := ValidateEnvKey("configKey", entry.ConfigKey); err != nil {
+		return err
+	}
 	return s.repo.Create(entry)
 }
 
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/quckapp/service-urls-api/internal/repository"
@@ -28,15 +30,105 @@ func NewConfigService(
 	}
 }
 
+// Config value sources, in the order they are applied. Later sources
+// override earlier ones when keys collide.
+const (
+	SourceService  = "service"
+	SourceInfra    = "infra"
+	SourceFirebase = "firebase"
+	SourceConfig   = "config"
+)
+
+// SourceSecret is a LookupKey filter, not a source of its own: it selects
+// config entries marked as secret.
+const SourceSecret = "secret"
+
+var (
+	ErrInvalidSource = errors.New("source must be one of service, infra, firebase, config, secret")
+	ErrKeyNotFound   = errors.New("key not found")
+)
+
+// ConfigValue is a single generated key with the record type it came from.
+type ConfigValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Source   string `json:"source"`
+	IsSecret bool   `json:"isSecret"`
+}
+
+// KeyLookup is the resolved value of a key plus any sources it shadows.
+type KeyLookup struct {
+	ConfigValue
+	Shadowed []string `json:"shadowed,omitempty"`
+}
+
 func (s *ConfigService) GetFlatConfig(env string) (map[string]string, error) {
-	result := make(map[string]string)
+	values, err := s.resolve(env)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(values))
+	for _, v := range values {
+		result[v.Key] = v.Value
+	}
+	return result, nil
+}
+
+// LookupKey returns where key comes from in env. With an empty source it
+// returns the effective value (the one GetFlatConfig emits); otherwise it
+// returns the value defined by that source, even if it is overridden.
+func (s *ConfigService) LookupKey(env, key, source string) (*KeyLookup, error) {
+	switch source {
+	case "", SourceService, SourceInfra, SourceFirebase, SourceConfig, SourceSecret:
+	default:
+		return nil, ErrInvalidSource
+	}
+
+	values, err := s.resolve(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []ConfigValue
+	for _, v := range values {
+		if v.Key == key && matchesSource(v, source) {
+			matches = append(matches, v)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %q in environment %q", ErrKeyNotFound, key, env)
+	}
+
+	result := &KeyLookup{ConfigValue: matches[len(matches)-1]}
+	for _, m := range matches[:len(matches)-1] {
+		result.Shadowed = append(result.Shadowed, m.Source)
+	}
+	return result, nil
+}
+
+func matchesSource(v ConfigValue, source string) bool {
+	switch source {
+	case "":
+		return true
+	case SourceSecret:
+		return v.Source == SourceConfig && v.IsSecret
+	}
+	return v.Source == source
+}
+
+// resolve loads every generated value for env in override order.
+func (s *ConfigService) resolve(env string) ([]ConfigValue, error) {
+	var result []ConfigValue
+	add := func(key, value, source string, secret bool) {
+		result = append(result, ConfigValue{Key: key, Value: value, Source: source, IsSecret: secret})
+	}
 
 	services, err := s.serviceUrlRepo.FindAllActiveByEnv(env)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load service urls: %w", err)
 	}
 	for _, svc := range services {
-		result[svc.ServiceKey] = svc.URL
+		add(svc.ServiceKey, svc.URL, SourceService, false)
 	}
 
 	infra, err := s.infraRepo.FindByEnv(env)
@@ -44,22 +136,22 @@ func (s *ConfigService) GetFlatConfig(env string) (map[string]string, error) {
 		return nil, fmt.Errorf("failed to load infrastructure: %w", err)
 	}
 	for _, i := range infra {
-		result[i.InfraKey+"_HOST"] = i.Host
-		result[i.InfraKey+"_PORT"] = fmt.Sprintf("%d", i.Port)
+		add(i.InfraKey+"_HOST", i.Host, SourceInfra, false)
+		add(i.InfraKey+"_PORT", fmt.Sprintf("%d", i.Port), SourceInfra, false)
 		if i.Username != "" {
-			result[i.InfraKey+"_USERNAME"] = i.Username
+			add(i.InfraKey+"_USERNAME", i.Username, SourceInfra, false)
 		}
 		if i.ConnectionString != "" {
-			result[i.InfraKey+"_CONNECTION_STRING"] = i.ConnectionString
+			add(i.InfraKey+"_CONNECTION_STRING", i.ConnectionString, SourceInfra, false)
 		}
 	}
 
 	fb, err := s.firebaseRepo.FindByEnv(env)
 	if err == nil && fb != nil {
-		result["FIREBASE_PROJECT_ID"] = fb.ProjectID
-		result["FIREBASE_CLIENT_EMAIL"] = fb.ClientEmail
-		result["FIREBASE_PRIVATE_KEY"] = fb.PrivateKey
-		result["FIREBASE_STORAGE_BUCKET"] = fb.StorageBucket
+		add("FIREBASE_PROJECT_ID", fb.ProjectID, SourceFirebase, false)
+		add("FIREBASE_CLIENT_EMAIL", fb.ClientEmail, SourceFirebase, false)
+		add("FIREBASE_PRIVATE_KEY", fb.PrivateKey, SourceFirebase, true)
+		add("FIREBASE_STORAGE_BUCKET", fb.StorageBucket, SourceFirebase, false)
 	}
 
 	// Config entries loaded last — can override any colliding keys (intentional escape hatch)
@@ -68,7 +160,7 @@ func (s *ConfigService) GetFlatConfig(env string) (map[string]string, error) {
 		return nil, fmt.Errorf("failed to load config entries: %w", err)
 	}
 	for _, e := range entries {
-		result[e.ConfigKey] = e.ConfigValue
+		add(e.ConfigKey, e.ConfigValue, SourceConfig, e.IsSecret)
 	}
 
 	return result, nil
@@ -76,7 +168,8 @@ func (s *ConfigService) GetFlatConfig(env string) (map[string]string, error) {
 
 func FormatEnvFile(config map[string]string) string {
 	var b strings.Builder
-	for k, v := range config {
+	for _, k := range sortedKeys(config) {
+		v := config[k]
 		if strings.ContainsAny(v, " \t=:#\"'\\") {
 			b.WriteString(fmt.Sprintf("%s=\"%s\"\n", k, strings.ReplaceAll(v, "\"", "\\\"")))
 		} else {
@@ -89,12 +182,22 @@ func FormatEnvFile(config map[string]string) string {
 func FormatDockerCompose(config map[string]string) string {
 	var b strings.Builder
 	b.WriteString("environment:\n")
-	for k, v := range config {
-		b.WriteString(fmt.Sprintf("  %s: \"%s\"\n", k, strings.ReplaceAll(v, "\"", "\\\"")))
+	for _, k := range sortedKeys(config) {
+		b.WriteString(fmt.Sprintf("  %s: \"%s\"\n", k, strings.ReplaceAll(config[k], "\"", "\\\"")))
 	}
 	return b.String()
 }
 
+// sortedKeys keeps generated output byte-for-byte stable so it can be cached by ETag.
+func sortedKeys(config map[string]string) []string {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (s *ConfigService) GetSingleValue(env, key string) (string, error) {
 	config, err := s.GetFlatConfig(env)
 	if err != nil {
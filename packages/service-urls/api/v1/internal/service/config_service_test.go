@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+func newConfigService(db *gorm.DB) *ConfigService {
+	return NewConfigService(
+		repository.NewServiceUrlRepository(db),
+		repository.NewInfrastructureRepository(db),
+		repository.NewFirebaseRepository(db),
+		repository.NewConfigEntryRepository(db),
+	)
+}
+
+// expectResolve stubs one full config resolution for qa, where CACHE_URL is
+// defined both as a service URL and as a (secret) config entry.
+func expectResolve(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("FROM `service_urls`").
+		WillReturnRows(sqlmock.NewRows([]string{"service_key", "url"}).
+			AddRow("AUTH_SERVICE_URL", "http://auth:8080").
+			AddRow("CACHE_URL", "redis://cache:6379"))
+	mock.ExpectQuery("FROM `infrastructure_configs`").
+		WillReturnRows(sqlmock.NewRows([]string{"infra_key", "host", "port"}).AddRow("MYSQL", "mysql", 3306))
+	mock.ExpectQuery("FROM `firebase_configs`").
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery("FROM `config_entries`").
+		WillReturnRows(sqlmock.NewRows([]string{"config_key", "config_value", "is_secret"}).
+			AddRow("JWT_SECRET", "s3cret", true).
+			AddRow("CACHE_URL", "redis://:pw@cache:6379", true))
+}
+
+func TestLookupKeyServiceHit(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectResolve(mock)
+
+	got, err := newConfigService(db).LookupKey("qa", "AUTH_SERVICE_URL", "")
+	if err != nil {
+		t.Fatalf("LookupKey returned error: %v", err)
+	}
+	if got.Value != "http://auth:8080" || got.Source != SourceService || got.IsSecret || len(got.Shadowed) != 0 {
+		t.Errorf("unexpected lookup: %+v", got)
+	}
+}
+
+func TestLookupKeySecretHit(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectResolve(mock)
+
+	got, err := newConfigService(db).LookupKey("qa", "JWT_SECRET", "")
+	if err != nil {
+		t.Fatalf("LookupKey returned error: %v", err)
+	}
+	if got.Value != "s3cret" || got.Source != SourceConfig || !got.IsSecret {
+		t.Errorf("unexpected lookup: %+v", got)
+	}
+}
+
+func TestLookupKeyCollision(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectResolve(mock)
+
+	effective, err := newConfigService(db).LookupKey("qa", "CACHE_URL", "")
+	if err != nil {
+		t.Fatalf("LookupKey returned error: %v", err)
+	}
+	if effective.Source != SourceConfig || effective.Value != "redis://:pw@cache:6379" {
+		t.Errorf("expected config entry to win, got %+v", effective)
+	}
+	if len(effective.Shadowed) != 1 || effective.Shadowed[0] != SourceService {
+		t.Errorf("expected service source to be reported as shadowed, got %v", effective.Shadowed)
+	}
+
+	expectResolve(mock)
+	fromService, err := newConfigService(db).LookupKey("qa", "CACHE_URL", SourceService)
+	if err != nil {
+		t.Fatalf("LookupKey with source returned error: %v", err)
+	}
+	if fromService.Source != SourceService || fromService.Value != "redis://cache:6379" {
+		t.Errorf("expected service value, got %+v", fromService)
+	}
+}
+
+func TestLookupKeySecretSource(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectResolve(mock)
+
+	got, err := newConfigService(db).LookupKey("qa", "JWT_SECRET", SourceSecret)
+	if err != nil {
+		t.Fatalf("LookupKey with secret source returned error: %v", err)
+	}
+	if got.Value != "s3cret" || got.Source != SourceConfig || !got.IsSecret {
+		t.Errorf("unexpected lookup: %+v", got)
+	}
+
+	// AUTH_SERVICE_URL only comes from a service URL, so the secret filter skips it.
+	expectResolve(mock)
+	if _, err := newConfigService(db).LookupKey("qa", "AUTH_SERVICE_URL", SourceSecret); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected a non-secret key not to match the secret source, got %v", err)
+	}
+}
+
+func TestLookupKeyDistinguishesMissingKeyFromDBError(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectResolve(mock)
+	if _, err := newConfigService(db).LookupKey("qa", "MISSING_KEY", ""); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	mock.ExpectQuery("FROM `service_urls`").WillReturnError(errors.New("dial tcp: connection refused"))
+	_, err := newConfigService(db).LookupKey("qa", "AUTH_SERVICE_URL", "")
+	if err == nil || errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected a DB failure not to look like a missing key, got %v", err)
+	}
+}
+
+func TestLookupKeyRejectsUnknownSource(t *testing.T) {
+	db, _ := newMockDB(t)
+	if _, err := newConfigService(db).LookupKey("qa", "CACHE_URL", "vault"); err != ErrInvalidSource {
+		t.Errorf("expected ErrInvalidSource, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/quckapp/service-urls-api/internal/repository"
+)
+
+// envKeyPattern matches names that are legal environment variables in .env
+// files, docker-compose and shells.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateEnvKey rejects keys that the config generators cannot emit as
+// environment variable names. field names the offending request field.
+func ValidateEnvKey(field, key string) error {
+	if envKeyPattern.MatchString(key) {
+		return nil
+	}
+	return &InvalidFieldError{
+		Field:   field,
+		Rule:    "env_key",
+		Message: fmt.Sprintf("%q is not a valid environment variable name; use letters, digits and underscores, starting with a letter or underscore", key),
+	}
+}
+
+// KeyLintIssue is one existing key that is not a valid environment variable name.
+type KeyLintIssue struct {
+	Kind    string `json:"kind"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+type KeyLintReport struct {
+	Environment string         `json:"environment"`
+	Checked     int            `json:"checked"`
+	Issues      []KeyLintIssue `json:"issues"`
+}
+
+// KeyLintService reports existing keys that were stored before key
+// validation existed, or that bypassed it.
+type KeyLintService struct {
+	serviceUrlRepo  *repository.ServiceUrlRepository
+	infraRepo       *repository.InfrastructureRepository
+	configEntryRepo *repository.ConfigEntryRepository
+}
+
+func NewKeyLintService(
+	serviceUrlRepo *repository.ServiceUrlRepository,
+	infraRepo *repository.InfrastructureRepository,
+	configEntryRepo *repository.ConfigEntryRepository,
+) *KeyLintService {
+	return &KeyLintService{
+		serviceUrlRepo:  serviceUrlRepo,
+		infraRepo:       infraRepo,
+		configEntryRepo: configEntryRepo,
+	}
+}
+
+// Lint checks every service URL, infrastructure and config entry key in env.
+func (s *KeyLintService) Lint(env string) (*KeyLintReport, error) {
+	report := &KeyLintReport{Environment: env, Issues: []KeyLintIssue{}}
+
+	services, err := s.serviceUrlRepo.FindByEnv(env, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load services: %w", err)
+	}
+	for _, svc := range services {
+		report.check(ImportKindService, svc.ServiceKey)
+	}
+
+	infra, err := s.infraRepo.FindByEnv(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load infrastructure: %w", err)
+	}
+	for _, inf := range infra {
+		report.check(ImportKindInfra, inf.InfraKey)
+	}
+
+	entries, err := s.configEntryRepo.FindByEnv(env, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config entries: %w", err)
+	}
+	for _, entry := range entries {
+		report.check(ImportKindConfigEntry, entry.ConfigKey)
+	}
+	return report, nil
+}
+
+func (r *KeyLintReport) check(kind, key string) {
+	r.Checked++
+	if err := ValidateEnvKey("key", key); err != nil {
+		r.Issues = append(r.Issues, KeyLintIssue{Kind: kind, Key: key, Message: err.Error()})
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+)
+
+func TestValidateEnvKeyAccepts(t *testing.T) {
+	for _, key := range []string{"AUTH_SERVICE_URL", "_PRIVATE", "redis", "S3", "KEY_2"} {
+		if err := ValidateEnvKey("serviceKey", key); err != nil {
+			t.Errorf("%q: expected valid, got %v", key, err)
+		}
+	}
+}
+
+func TestValidateEnvKeyRejects(t *testing.T) {
+	for _, key := range []string{"", "2FA_SECRET", "AUTH SERVICE", "auth-service", "API.KEY", "KEY=VALUE", "ÜBER"} {
+		err := ValidateEnvKey("serviceKey", key)
+		var fe *InvalidFieldError
+		if !errors.As(err, &fe) {
+			t.Errorf("%q: expected InvalidFieldError, got %v", key, err)
+			continue
+		}
+		if fe.Field != "serviceKey" || fe.Rule != "env_key" {
+			t.Errorf("%q: unexpected field error %+v", key, fe)
+		}
+	}
+}
+
+func TestCreateRejectsInvalidKey(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	// sqlmock fails on the INSERT if validation lets the key through.
+	if err := NewServiceUrlService(repository.NewServiceUrlRepository(db)).Create(&model.ServiceUrl{ServiceKey: "auth service"}); err == nil {
+		t.Error("expected service create to reject the key")
+	}
+	if err := NewInfrastructureService(repository.NewInfrastructureRepository(db)).Create(&model.InfrastructureConfig{InfraKey: "my-sql"}); err == nil {
+		t.Error("expected infrastructure create to reject the key")
+	}
+	if err := NewConfigEntryService(repository.NewConfigEntryRepository(db)).Create(&model.ConfigEntry{ConfigKey: "1PASSWORD"}); err == nil {
+		t.Error("expected config entry create to reject the key")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+}
+
+func TestKeyLintReport(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewKeyLintService(
+		repository.NewServiceUrlRepository(db),
+		repository.NewInfrastructureRepository(db),
+		repository.NewConfigEntryRepository(db),
+	)
+
+	mock.ExpectQuery("FROM `service_urls` WHERE environment = \\? AND is_active = \\?").
+		WithArgs("qa", true).
+		WillReturnRows(sqlmock.NewRows([]string{"service_key"}).AddRow("AUTH_SERVICE_URL").AddRow("file service url"))
+	mock.ExpectQuery("FROM `infrastructure_configs` WHERE environment = \\? AND is_active = \\?").
+		WithArgs("qa", true).
+		WillReturnRows(sqlmock.NewRows([]string{"infra_key"}).AddRow("MYSQL"))
+	mock.ExpectQuery("FROM `config_entries` WHERE environment = \\? AND is_active = \\?").
+		WithArgs("qa", true).
+		WillReturnRows(sqlmock.NewRows([]string{"config_key"}).AddRow("JWT_SECRET").AddRow("sentry.dsn"))
+
+	report, err := svc.Lint("qa")
+	if err != nil {
+		t.Fatalf("Lint returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+
+	if report.Checked != 5 {
+		t.Errorf("expected 5 keys checked, got %d", report.Checked)
+	}
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", report.Issues)
+	}
+	if report.Issues[0].Kind != ImportKindService || report.Issues[0].Key != "file service url" {
+		t.Errorf("unexpected first issue: %+v", report.Issues[0])
+	}
+	if report.Issues[1].Kind != ImportKindConfigEntry || report.Issues[1].Key != "sentry.dsn" {
+		t.Errorf("unexpected second issue: %+v", report.Issues[1])
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEnvironmentLocked    = errors.New("environment is locked")
+	ErrEnvironmentNotLocked = errors.New("environment is not locked")
+)
+
+type EnvironmentLockService struct {
+	repo *repository.EnvironmentLockRepository
+}
+
+func NewEnvironmentLockService(repo *repository.EnvironmentLockRepository) *EnvironmentLockService {
+	return &EnvironmentLockService{repo: repo}
+}
+
+// Get returns the lock on env, or nil if the environment is not locked.
+func (s *EnvironmentLockService) Get(env string) (*model.EnvironmentLock, error) {
+	lock, err := s.repo.FindByEnv(env)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return lock, err
+}
+
+func (s *EnvironmentLockService) List() ([]model.EnvironmentLock, error) {
+	return s.repo.FindAll()
+}
+
+func (s *EnvironmentLockService) Lock(env, lockedBy, reason string) (*model.EnvironmentLock, error) {
+	existing, err := s.Get(env)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w by %s", ErrEnvironmentLocked, existing.LockedBy)
+	}
+	lock := &model.EnvironmentLock{Environment: env, LockedBy: lockedBy, Reason: reason}
+	err = s.repo.Create(lock)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// A concurrent request locked env between Get and Create.
+		if winner, _ := s.Get(env); winner != nil {
+			return nil, fmt.Errorf("%w by %s", ErrEnvironmentLocked, winner.LockedBy)
+		}
+		return nil, ErrEnvironmentLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+func (s *EnvironmentLockService) Unlock(env string) error {
+	n, err := s.repo.Delete(env)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrEnvironmentNotLocked
+	}
+	return nil
+}
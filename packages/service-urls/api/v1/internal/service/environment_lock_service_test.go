@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/quckapp/service-urls-api/internal/repository"
+)
+
+func TestLockConcurrentDuplicateIsLocked(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewEnvironmentLockService(repository.NewEnvironmentLockRepository(db))
+
+	// Another request inserts its lock between our check and our insert.
+	mock.ExpectQuery("FROM `environment_locks` WHERE environment = \\?").WillReturnRows(emptyRows())
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `environment_locks`").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'production' for key 'environment'"})
+	mock.ExpectRollback()
+	mock.ExpectQuery("FROM `environment_locks` WHERE environment = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"environment", "locked_by"}).AddRow("production", "release-manager"))
+
+	_, err := svc.Lock("production", "ops", "freeze")
+	if !errors.Is(err, ErrEnvironmentLocked) {
+		t.Fatalf("expected ErrEnvironmentLocked, got %v", err)
+	}
+	if err.Error() != "environment is locked by release-manager" {
+		t.Errorf("expected the winning lock holder in the error, got %q", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+}
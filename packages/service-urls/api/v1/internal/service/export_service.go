@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BulkExportResponse struct {
+	Environment    string                       `json:"environment"`
+	Services       []model.ServiceUrl           `json:"services"`
+	Infrastructure []model.InfrastructureConfig `json:"infrastructure"`
+	Firebase       *model.FirebaseConfig        `json:"firebase"`
+	// FirebasePrivateKey is only set when secrets are included, so an
+	// all-environment export can restore Firebase through ImportAll.
+	FirebasePrivateKey string              `json:"firebasePrivateKey,omitempty"`
+	ConfigEntries      []model.ConfigEntry `json:"configEntries"`
+}
+
+type ExportService struct {
+	serviceUrlRepo  *repository.ServiceUrlRepository
+	infraRepo       *repository.InfrastructureRepository
+	firebaseRepo    *repository.FirebaseRepository
+	configEntryRepo *repository.ConfigEntryRepository
+}
+
+func NewExportService(
+	serviceUrlRepo *repository.ServiceUrlRepository,
+	infraRepo *repository.InfrastructureRepository,
+	firebaseRepo *repository.FirebaseRepository,
+	configEntryRepo *repository.ConfigEntryRepository,
+) *ExportService {
+	return &ExportService{
+		serviceUrlRepo:  serviceUrlRepo,
+		infraRepo:       infraRepo,
+		firebaseRepo:    firebaseRepo,
+		configEntryRepo: configEntryRepo,
+	}
+}
+
+// Export returns everything configured for env. Secret config values are
+// masked, and the Firebase private key left out, unless includeSecrets is set.
+func (s *ExportService) Export(env string, includeSecrets bool) (*BulkExportResponse, error) {
+	services, err := s.serviceUrlRepo.FindByEnv(env, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load services for %s: %w", env, err)
+	}
+	infra, err := s.infraRepo.FindByEnv(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load infrastructure for %s: %w", env, err)
+	}
+	fb, err := s.firebaseRepo.FindByEnv(env)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load firebase config for %s: %w", env, err)
+	}
+	if fb != nil {
+		fb.MaskPrivateKey()
+	}
+	entries, err := s.configEntryRepo.FindByEnv(env, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config entries for %s: %w", env, err)
+	}
+	if !includeSecrets {
+		for i := range entries {
+			entries[i].MaskValue()
+		}
+	}
+
+	export := &BulkExportResponse{
+		Environment:    env,
+		Services:       services,
+		Infrastructure: infra,
+		Firebase:       fb,
+		ConfigEntries:  entries,
+	}
+	if fb != nil && includeSecrets {
+		export.FirebasePrivateKey = fb.PrivateKey
+	}
+	return export, nil
+}
+
+// ExportAll exports every environment, keyed by environment name.
+func (s *ExportService) ExportAll(includeSecrets bool) (map[string]*BulkExportResponse, error) {
+	all := make(map[string]*BulkExportResponse, len(Environments))
+	for _, env := range Environments {
+		export, err := s.Export(env, includeSecrets)
+		if err != nil {
+			return nil, err
+		}
+		all[env] = export
+	}
+	return all, nil
+}
@@ -0,0 +1,595 @@
+package service
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BulkImportRequest struct {
+	Services       []model.ServiceUrl           `json:"services"`
+	Infrastructure []model.InfrastructureConfig `json:"infrastructure"`
+	ConfigEntries  []model.ConfigEntry          `json:"configEntries"`
+	Firebase       *model.FirebaseConfig        `json:"firebase"`
+	// FirebasePrivateKey restores the key an export carries when secrets are
+	// included. When empty, an existing Firebase config keeps its key.
+	FirebasePrivateKey string `json:"firebasePrivateKey"`
+	// ConfirmToken, when set, must equal the token returned by validating the
+	// same request; the import is refused if the plan has changed since.
+	ConfirmToken string `json:"confirmToken"`
+	// Overwrite replaces the values of existing keys (last write wins).
+	// By default existing keys are skipped and only new keys are created.
+	Overwrite bool `json:"overwrite"`
+}
+
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionSkip   ImportAction = "skip"
+)
+
+// Import item kinds.
+const (
+	ImportKindService     = "service"
+	ImportKindInfra       = "infrastructure"
+	ImportKindConfigEntry = "configEntry"
+	ImportKindFirebase    = "firebase"
+)
+
+// firebaseImportKey labels the single Firebase config of an environment in
+// plans and results.
+const firebaseImportKey = "FIREBASE"
+
+var (
+	ErrConfirmTokenMismatch = errors.New("confirm token does not match the current import plan; validate again")
+	ErrConfirmTokenRequired = errors.New("confirm token is required; validate the import first")
+	ErrUnknownEnvironment   = errors.New("unknown environment")
+	ErrMaskedSecret         = errors.New("secret values are masked; export with secrets included to import them")
+)
+
+type ImportPlanItem struct {
+	Kind     string       `json:"kind"`
+	Key      string       `json:"key"`
+	Action   ImportAction `json:"action"`
+	OldValue string       `json:"oldValue,omitempty"`
+	NewValue string       `json:"newValue"`
+}
+
+// ImportPlan describes what an import would do without writing anything.
+// Secret values are masked.
+type ImportPlan struct {
+	Environment  string           `json:"environment"`
+	Items        []ImportPlanItem `json:"items"`
+	Creates      int              `json:"creates"`
+	Updates      int              `json:"updates"`
+	Skips        int              `json:"skips"`
+	ConfirmToken string           `json:"confirmToken"`
+}
+
+// ImportResult reports what an import wrote. Overwritten lists every
+// existing key whose value was replaced, with secret values masked.
+type ImportResult struct {
+	Imported    int              `json:"imported"`
+	Created     int              `json:"created"`
+	Updated     int              `json:"updated"`
+	Skipped     int              `json:"skipped"`
+	Overwritten []ImportPlanItem `json:"overwritten"`
+}
+
+// ImportAllRequest restores several environments at once, e.g. from
+// an all-environments export. ConfirmToken is mandatory.
+type ImportAllRequest struct {
+	Environments map[string]*BulkImportRequest `json:"environments" binding:"required"`
+	ConfirmToken string                        `json:"confirmToken"`
+}
+
+// ImportAllPlan holds one plan per environment and a token covering them all.
+type ImportAllPlan struct {
+	Plans        map[string]*ImportPlan `json:"plans"`
+	ConfirmToken string                 `json:"confirmToken"`
+}
+
+// ImportService holds the database rather than repositories so that Import
+// can read and write inside one transaction.
+type ImportService struct {
+	db              *gorm.DB
+	serviceUrlRepo  *repository.ServiceUrlRepository
+	infraRepo       *repository.InfrastructureRepository
+	configEntryRepo *repository.ConfigEntryRepository
+	firebaseRepo    *repository.FirebaseRepository
+}
+
+func NewImportService(db *gorm.DB) *ImportService {
+	return &ImportService{
+		db:              db,
+		serviceUrlRepo:  repository.NewServiceUrlRepository(db),
+		infraRepo:       repository.NewInfrastructureRepository(db),
+		configEntryRepo: repository.NewConfigEntryRepository(db),
+		firebaseRepo:    repository.NewFirebaseRepository(db),
+	}
+}
+
+// Plan classifies every item in the request against the current state of env.
+// Existing keys are reported as "skip", or as "update" when req.Overwrite is
+// set and the value differs.
+func (s *ImportService) Plan(env string, req *BulkImportRequest) (*ImportPlan, error) {
+	plan := &ImportPlan{Environment: env, Items: []ImportPlanItem{}}
+	token := sha256.New()
+	fmt.Fprintf(token, "%s\n", env)
+
+	for _, svc := range req.Services {
+		existing, err := s.serviceUrlRepo.FindByEnvAndKey(env, svc.ServiceKey)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to look up service %q: %w", svc.ServiceKey, err)
+		}
+		var old *string
+		if existing != nil {
+			old = &existing.URL
+		}
+		plan.add(token, ImportKindService, svc.ServiceKey, old, svc.URL, nil, req.Overwrite)
+	}
+
+	for _, inf := range req.Infrastructure {
+		existing, err := s.infraRepo.FindByEnvAndKey(env, inf.InfraKey)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to look up infrastructure %q: %w", inf.InfraKey, err)
+		}
+		var old *string
+		if existing != nil {
+			v := infraValue(existing)
+			old = &v
+		}
+		plan.add(token, ImportKindInfra, inf.InfraKey, old, infraValue(&inf), maskCredentials, req.Overwrite)
+	}
+
+	for _, entry := range req.ConfigEntries {
+		existing, err := s.configEntryRepo.FindByEnvAndKey(env, entry.ConfigKey)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to look up config entry %q: %w", entry.ConfigKey, err)
+		}
+		secret := entry.IsSecret
+		newValue := entry.ConfigValue
+		var old *string
+		if existing != nil {
+			old = &existing.ConfigValue
+			secret = secret || existing.IsSecret
+			if keepsSecretValue(existing, &entry) {
+				newValue = existing.ConfigValue
+			}
+		}
+		plan.add(token, ImportKindConfigEntry, entry.ConfigKey, old, newValue, secretMask(secret), req.Overwrite)
+	}
+
+	if req.Firebase != nil {
+		existing, err := notFoundAsNil(s.firebaseRepo.FindByEnv(env))
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up firebase config: %w", err)
+		}
+		var old *string
+		if existing != nil {
+			v := firebaseValue(existing, existing.PrivateKey)
+			old = &v
+		}
+		newValue := firebaseValue(req.Firebase, importedFirebaseKey(existing, req))
+		plan.add(token, ImportKindFirebase, firebaseImportKey, old, newValue, maskFirebaseKey, req.Overwrite)
+	}
+
+	plan.ConfirmToken = fmt.Sprintf("%x", token.Sum(nil))
+	return plan, nil
+}
+
+// Import creates every item that does not already exist in env and, when
+// req.Overwrite is set, updates existing items whose value differs. Existing
+// values are read before writing within one transaction, so the result
+// reports exactly what was overwritten. If req.ConfirmToken is set it must
+// match a fresh Plan. Nothing is written if any item fails.
+func (s *ImportService) Import(env string, req *BulkImportRequest) (*ImportResult, error) {
+	var result *ImportResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = NewImportService(tx).importEnv(env, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importEnv runs one environment's import on s, which must be bound to a
+// transaction.
+func (s *ImportService) importEnv(env string, req *BulkImportRequest) (*ImportResult, error) {
+	if req.ConfirmToken != "" {
+		plan, err := s.Plan(env, req)
+		if err != nil {
+			return nil, err
+		}
+		if plan.ConfirmToken != req.ConfirmToken {
+			return nil, ErrConfirmTokenMismatch
+		}
+	}
+	result := &ImportResult{Overwritten: []ImportPlanItem{}}
+	if err := s.apply(env, req, result); err != nil {
+		return nil, err
+	}
+	result.Imported = result.Created + result.Updated
+	return result, nil
+}
+
+func (s *ImportService) apply(env string, req *BulkImportRequest, result *ImportResult) error {
+	for i := range req.Services {
+		svc := &req.Services[i]
+		svc.Environment = env
+		existing, err := notFoundAsNil(s.serviceUrlRepo.FindByEnvAndKey(env, svc.ServiceKey))
+		if err != nil {
+			return fmt.Errorf("failed to look up service %q: %w", svc.ServiceKey, err)
+		}
+		switch {
+		case existing == nil:
+			if err := ValidateEnvKey(fmt.Sprintf("services[%d].serviceKey", i), svc.ServiceKey); err != nil {
+				return err
+			}
+			// IDs in an export belong to the source environment.
+			svc.ID = uuid.Nil
+			err = s.serviceUrlRepo.Create(svc)
+			result.Created++
+		case !req.Overwrite || existing.URL == svc.URL:
+			result.Skipped++
+		default:
+			result.overwrote(ImportKindService, svc.ServiceKey, existing.URL, svc.URL, nil)
+			existing.URL = svc.URL
+			if svc.Category != "" {
+				existing.Category = svc.Category
+			}
+			if svc.Description != "" {
+				existing.Description = svc.Description
+			}
+			err = s.serviceUrlRepo.Update(existing)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import service %q: %w", svc.ServiceKey, err)
+		}
+	}
+
+	for i := range req.Infrastructure {
+		inf := &req.Infrastructure[i]
+		inf.Environment = env
+		existing, err := notFoundAsNil(s.infraRepo.FindByEnvAndKey(env, inf.InfraKey))
+		if err != nil {
+			return fmt.Errorf("failed to look up infrastructure %q: %w", inf.InfraKey, err)
+		}
+		switch {
+		case existing == nil:
+			if err := ValidateEnvKey(fmt.Sprintf("infrastructure[%d].infraKey", i), inf.InfraKey); err != nil {
+				return err
+			}
+			if err := validateImportedInfra(i, inf); err != nil {
+				return err
+			}
+			inf.ID = uuid.Nil
+			err = s.infraRepo.Create(inf)
+			result.Created++
+		case !req.Overwrite || infraValue(existing) == infraValue(inf):
+			result.Skipped++
+		default:
+			result.overwrote(ImportKindInfra, inf.InfraKey, infraValue(existing), infraValue(inf), maskCredentials)
+			existing.Host = inf.Host
+			existing.Port = inf.Port
+			existing.Username = inf.Username
+			existing.ConnectionString = inf.ConnectionString
+			if err := validateImportedInfra(i, existing); err != nil {
+				return err
+			}
+			err = s.infraRepo.Update(existing)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import infrastructure %q: %w", inf.InfraKey, err)
+		}
+	}
+
+	for i := range req.ConfigEntries {
+		entry := &req.ConfigEntries[i]
+		entry.Environment = env
+		existing, err := notFoundAsNil(s.configEntryRepo.FindByEnvAndKey(env, entry.ConfigKey))
+		if err != nil {
+			return fmt.Errorf("failed to look up config entry %q: %w", entry.ConfigKey, err)
+		}
+		switch {
+		case existing == nil:
+			if err := ValidateEnvKey(fmt.Sprintf("configEntries[%d].configKey", i), entry.ConfigKey); err != nil {
+				return err
+			}
+			if entry.IsSecret && entry.ConfigValue == "****" {
+				return fmt.Errorf("%w: %s", ErrMaskedSecret, entry.ConfigKey)
+			}
+			entry.ID = uuid.Nil
+			err = s.configEntryRepo.Create(entry)
+			result.Created++
+		case !req.Overwrite || existing.ConfigValue == entry.ConfigValue || keepsSecretValue(existing, entry):
+			result.Skipped++
+		default:
+			// A key that was secret stays secret even if the import omits the flag.
+			secret := existing.IsSecret || entry.IsSecret
+			result.overwrote(ImportKindConfigEntry, entry.ConfigKey, existing.ConfigValue, entry.ConfigValue, secretMask(secret))
+			existing.ConfigValue = entry.ConfigValue
+			existing.IsSecret = secret
+			if entry.Category != "" {
+				existing.Category = entry.Category
+			}
+			if entry.Description != "" {
+				existing.Description = entry.Description
+			}
+			err = s.configEntryRepo.Update(existing)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import config entry %q: %w", entry.ConfigKey, err)
+		}
+	}
+
+	if req.Firebase != nil {
+		if err := s.applyFirebase(env, req, result); err != nil {
+			return fmt.Errorf("failed to import firebase config: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyFirebase creates or, when overwriting, replaces env's Firebase config.
+// A new config needs the private key; an existing one keeps its key when the
+// import carries none.
+func (s *ImportService) applyFirebase(env string, req *BulkImportRequest, result *ImportResult) error {
+	existing, err := notFoundAsNil(s.firebaseRepo.FindByEnv(env))
+	if err != nil {
+		return err
+	}
+	key := importedFirebaseKey(existing, req)
+	fb := *req.Firebase
+	fb.Environment = env
+	fb.PrivateKey = key
+
+	switch {
+	case existing == nil:
+		if key == "" {
+			return fmt.Errorf("%w: firebase private key", ErrMaskedSecret)
+		}
+		fb.ID = uuid.Nil
+		result.Created++
+	case !req.Overwrite || firebaseValue(existing, existing.PrivateKey) == firebaseValue(&fb, key):
+		result.Skipped++
+		return nil
+	default:
+		result.overwrote(ImportKindFirebase, firebaseImportKey,
+			firebaseValue(existing, existing.PrivateKey), firebaseValue(&fb, key), maskFirebaseKey)
+	}
+	return s.firebaseRepo.Upsert(&fb)
+}
+
+// validateImportedInfra applies the UpsertInfra connection string check to
+// the i-th imported infrastructure item.
+func validateImportedInfra(i int, inf *model.InfrastructureConfig) error {
+	err := ValidateConnectionString(inf)
+	var fe *InvalidFieldError
+	if errors.As(err, &fe) {
+		return &InvalidFieldError{Field: fmt.Sprintf("infrastructure[%d].%s", i, fe.Field), Rule: fe.Rule, Message: fe.Message}
+	}
+	return err
+}
+
+func (r *ImportResult) overwrote(kind, key, oldValue, newValue string, mask func(string) string) {
+	if mask != nil {
+		oldValue = mask(oldValue)
+		newValue = mask(newValue)
+	}
+	r.Updated++
+	r.Overwritten = append(r.Overwritten, ImportPlanItem{
+		Kind: kind, Key: key, Action: ImportActionUpdate, OldValue: oldValue, NewValue: newValue,
+	})
+}
+
+// PlanAll plans every environment in req. It rejects unknown environments and
+// secret entries that carry the export mask instead of a real value.
+func (s *ImportService) PlanAll(req *ImportAllRequest) (*ImportAllPlan, error) {
+	all := &ImportAllPlan{Plans: make(map[string]*ImportPlan, len(req.Environments))}
+	token := sha256.New()
+
+	for _, env := range Environments {
+		envReq, ok := req.Environments[env]
+		if !ok || envReq == nil {
+			continue
+		}
+		for _, entry := range envReq.ConfigEntries {
+			if entry.IsSecret && entry.ConfigValue == "****" {
+				return nil, fmt.Errorf("%w: %s/%s", ErrMaskedSecret, env, entry.ConfigKey)
+			}
+		}
+		plan, err := s.Plan(env, envReq)
+		if err != nil {
+			return nil, err
+		}
+		all.Plans[env] = plan
+		fmt.Fprintf(token, "%s\x00%s\n", env, plan.ConfirmToken)
+	}
+	for env := range req.Environments {
+		if _, ok := all.Plans[env]; !ok && req.Environments[env] != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEnvironment, env)
+		}
+	}
+
+	all.ConfirmToken = fmt.Sprintf("%x", token.Sum(nil))
+	return all, nil
+}
+
+// ImportAll imports every environment in req in a single transaction after
+// checking its confirm token against a fresh PlanAll, so a failure in any
+// environment leaves all of them untouched. Target environment locks are
+// checked inside the transaction. It returns the number imported per
+// environment.
+func (s *ImportService) ImportAll(req *ImportAllRequest) (map[string]int, error) {
+	if req.ConfirmToken == "" {
+		return nil, ErrConfirmTokenRequired
+	}
+
+	imported := make(map[string]int, len(req.Environments))
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txSvc := NewImportService(tx)
+		plan, err := txSvc.PlanAll(req)
+		if err != nil {
+			return err
+		}
+		if plan.ConfirmToken != req.ConfirmToken {
+			return ErrConfirmTokenMismatch
+		}
+
+		lockRepo := repository.NewEnvironmentLockRepository(tx)
+		for _, env := range Environments {
+			if _, ok := plan.Plans[env]; !ok {
+				continue
+			}
+			lock, err := notFoundAsNil(lockRepo.FindByEnvForShare(env))
+			if err != nil {
+				return fmt.Errorf("failed to check environment lock: %w", err)
+			}
+			if lock != nil {
+				return fmt.Errorf("%w: %s by %s", ErrEnvironmentLocked, env, lock.LockedBy)
+			}
+
+			envReq := *req.Environments[env]
+			envReq.ConfirmToken = ""
+			result, err := txSvc.importEnv(env, &envReq)
+			if err != nil {
+				return fmt.Errorf("failed to import %s: %w", env, err)
+			}
+			imported[env] = result.Imported
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// add records one item. The confirm token covers raw values, while the
+// reported values are passed through mask, if set.
+func (p *ImportPlan) add(token hash.Hash, kind, key string, old *string, newValue string, mask func(string) string, overwrite bool) {
+	item := ImportPlanItem{Kind: kind, Key: key, Action: ImportActionCreate, NewValue: newValue}
+	oldRaw := ""
+	if old != nil {
+		item.Action = ImportActionSkip
+		if overwrite && *old != newValue {
+			item.Action = ImportActionUpdate
+		}
+		item.OldValue = *old
+		oldRaw = *old
+	}
+	fmt.Fprintf(token, "%s\x00%s\x00%s\x00%s\x00%s\n", kind, key, item.Action, oldRaw, newValue)
+
+	if mask != nil {
+		item.NewValue = mask(item.NewValue)
+		item.OldValue = mask(item.OldValue)
+	}
+
+	switch item.Action {
+	case ImportActionCreate:
+		p.Creates++
+	case ImportActionUpdate:
+		p.Updates++
+	case ImportActionSkip:
+		p.Skips++
+	}
+	p.Items = append(p.Items, item)
+}
+
+// infraValue summarises the fields an import may change, so two values are
+// equal only if host, port, username and connection string all match. It
+// contains credentials; report it through maskCredentials.
+func infraValue(i *model.InfrastructureConfig) string {
+	v := net.JoinHostPort(i.Host, strconv.Itoa(i.Port))
+	if i.Username != "" {
+		v = i.Username + "@" + v
+	}
+	if i.ConnectionString != "" {
+		v += " " + i.ConnectionString
+	}
+	return v
+}
+
+var (
+	userinfoPassword = regexp.MustCompile(`(://[^:/@\s]*):([^@\s]+)@`)
+	queryPassword    = regexp.MustCompile(`(?i)\b(password|passwd|pwd)=[^&\s]+`)
+	envPlaceholder   = regexp.MustCompile(`^\$\{[A-Za-z0-9_]+\}$`)
+)
+
+// maskCredentials replaces passwords in connection strings, in the userinfo
+// or as a query parameter, with "****". ${VAR} placeholders are left as they
+// are since they carry no secret.
+func maskCredentials(v string) string {
+	v = userinfoPassword.ReplaceAllStringFunc(v, func(m string) string {
+		parts := userinfoPassword.FindStringSubmatch(m)
+		if envPlaceholder.MatchString(parts[2]) {
+			return m
+		}
+		return parts[1] + ":****@"
+	})
+	return queryPassword.ReplaceAllString(v, "$1=****")
+}
+
+func secretMask(secret bool) func(string) string {
+	if secret {
+		return maskSecret
+	}
+	return nil
+}
+
+// importedFirebaseKey is the private key an import would store: the one it
+// carries, or else the existing key.
+func importedFirebaseKey(existing *model.FirebaseConfig, req *BulkImportRequest) string {
+	if req.FirebasePrivateKey != "" || existing == nil {
+		return req.FirebasePrivateKey
+	}
+	return existing.PrivateKey
+}
+
+// firebaseValue summarises a Firebase config, including its private key so
+// that a key change counts as a change. Report it through maskFirebaseKey.
+func firebaseValue(fb *model.FirebaseConfig, privateKey string) string {
+	return fmt.Sprintf("%s %s %s active=%t privateKey=%s",
+		fb.ProjectID, fb.ClientEmail, fb.StorageBucket, fb.IsActive, privateKey)
+}
+
+func maskFirebaseKey(v string) string {
+	if i := strings.LastIndex(v, " privateKey="); i >= 0 {
+		return v[:i] + " privateKey=" + maskSecret(v[i+len(" privateKey="):])
+	}
+	return v
+}
+
+// keepsSecretValue reports whether an imported entry carries no real value
+// for an existing secret, as in an export without secrets. The stored value
+// is kept, matching ConfigEntryService.Update.
+func keepsSecretValue(existing, entry *model.ConfigEntry) bool {
+	return (existing.IsSecret || entry.IsSecret) && isMaskedValue(entry.ConfigValue)
+}
+
+func isMaskedValue(v string) bool {
+	return v == "" || v == "****"
+}
+
+func maskSecret(v string) string {
+	if v == "" {
+		return ""
+	}
+	return "****"
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/quckapp/service-urls-api/internal/model"
+)
+
+// InvalidFieldError reports a request field that failed a semantic check.
+type InvalidFieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *InvalidFieldError) Error() string { return e.Message }
+
+// Infra types that support connection string generation.
+const (
+	InfraTypeMySQL   = "mysql"
+	InfraTypeRedis   = "redis"
+	InfraTypeMongoDB = "mongodb"
+)
+
+var defaultSchemePorts = map[string]int{
+	"http":       80,
+	"https":      443,
+	"mysql":      3306,
+	"postgres":   5432,
+	"postgresql": 5432,
+	"redis":      6379,
+	"rediss":     6379,
+	"mongodb":    27017,
+}
+
+// placeholderEnvSuffix maps environments to the suffix used in the seeded
+// password placeholders; live shares production's credentials.
+var placeholderEnvSuffix = map[string]string{
+	"development": "DEV",
+	"production":  "PROD",
+	"live":        "PROD",
+}
+
+// InfraType infers the generation template from the infra key, e.g.
+// "MYSQL" or "CACHE_REDIS". It returns "" for unknown types.
+func InfraType(infraKey string) string {
+	key := strings.ToUpper(infraKey)
+	switch {
+	case strings.Contains(key, "MYSQL"):
+		return InfraTypeMySQL
+	case strings.Contains(key, "REDIS"):
+		return InfraTypeRedis
+	case strings.Contains(key, "MONGO"):
+		return InfraTypeMongoDB
+	}
+	return ""
+}
+
+// GenerateConnectionString builds a connection string from the structured
+// fields. The password is left as a ${<KEY>_<ENV>_PASSWORD} placeholder,
+// e.g. ${MONGO_PROD_PASSWORD}, matching how secrets are referenced in the
+// seeded configs.
+func GenerateConnectionString(i *model.InfrastructureConfig) (string, error) {
+	typ := InfraType(i.InfraKey)
+	if typ == "" {
+		return "", &InvalidFieldError{
+			Field:   "infraKey",
+			Rule:    "infra_type",
+			Message: fmt.Sprintf("cannot generate a connection string for %s; supported types are mysql, redis and mongodb", i.InfraKey),
+		}
+	}
+	if i.Host == "" || i.Port <= 0 {
+		return "", &InvalidFieldError{
+			Field:   "host",
+			Rule:    "required",
+			Message: "host and port are required to generate a connection string",
+		}
+	}
+
+	hostPort := net.JoinHostPort(i.Host, strconv.Itoa(i.Port))
+	password := passwordPlaceholder(i)
+	switch typ {
+	case InfraTypeRedis:
+		return fmt.Sprintf("redis://%s:%s@%s", i.Username, password, hostPort), nil
+	default:
+		if i.Username == "" {
+			return fmt.Sprintf("%s://%s", typ, hostPort), nil
+		}
+		return fmt.Sprintf("%s://%s:%s@%s", typ, i.Username, password, hostPort), nil
+	}
+}
+
+// passwordPlaceholder returns ${<KEY>_<ENV>_PASSWORD}, or ${<KEY>_PASSWORD}
+// when the infra has no environment.
+func passwordPlaceholder(i *model.InfrastructureConfig) string {
+	name := strings.ToUpper(i.InfraKey)
+	if i.Environment != "" {
+		suffix, ok := placeholderEnvSuffix[i.Environment]
+		if !ok {
+			suffix = strings.ToUpper(i.Environment)
+		}
+		name += "_" + suffix
+	}
+	return "${" + name + "_PASSWORD}"
+}
+
+// ValidateConnectionString checks that a connection string, when present,
+// points at the same host and port as the structured fields. Multi-host
+// strings (replica sets, broker lists) pass if any listed host matches.
+func ValidateConnectionString(i *model.InfrastructureConfig) error {
+	if i.ConnectionString == "" {
+		return nil
+	}
+
+	scheme, hosts, err := parseConnectionHosts(i.ConnectionString)
+	if err != nil {
+		return &InvalidFieldError{Field: "connectionString", Rule: "parse", Message: err.Error()}
+	}
+
+	for _, h := range hosts {
+		host, port, err := splitHostPort(h, scheme)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(host, i.Host) && (port == 0 || port == i.Port) {
+			return nil
+		}
+	}
+	return &InvalidFieldError{
+		Field:   "connectionString",
+		Rule:    "host_match",
+		Message: fmt.Sprintf("connection string host %s does not match %s", strings.Join(hosts, ","), net.JoinHostPort(i.Host, strconv.Itoa(i.Port))),
+	}
+}
+
+// parseConnectionHosts extracts the scheme and host list from a connection
+// string. It avoids net/url because stored credentials may contain
+// ${VAR} placeholders, which url.Parse rejects in userinfo.
+func parseConnectionHosts(conn string) (string, []string, error) {
+	scheme := ""
+	rest := conn
+	if idx := strings.Index(conn, "://"); idx >= 0 {
+		scheme = strings.ToLower(conn[:idx])
+		rest = conn[idx+3:]
+	}
+	if end := strings.IndexAny(rest, "/?#"); end >= 0 {
+		rest = rest[:end]
+	}
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		rest = rest[at+1:]
+	}
+	if rest == "" {
+		return "", nil, fmt.Errorf("connection string %q has no host", conn)
+	}
+	return scheme, strings.Split(rest, ","), nil
+}
+
+// splitHostPort returns port 0 when the string has no port and the scheme
+// has no well-known default.
+func splitHostPort(hostPort, scheme string) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return strings.Trim(hostPort, "[]"), defaultSchemePorts[scheme], nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port %q", portStr)
+	}
+	return host, port, nil
+}
@@ -18,6 +18,12 @@ func (s *InfrastructureService) List(env string) ([]model.InfrastructureConfig,
 }
 
 func (s *InfrastructureService) Create(infra *model.InfrastructureConfig) error {
+	if err := ValidateEnvKey("infraKey", infra.InfraKey); err != nil {
+		return err
+	}
+	if err := ValidateConnectionString(infra); err != nil {
+		return err
+	}
 	return s.repo.Create(infra)
 }
 
@@ -29,9 +35,23 @@ func (s *InfrastructureService) Update(env, key string, updates *model.Infrastru
 	updates.ID = existing.ID
 	updates.Environment = env
 	updates.InfraKey = key
+	if err := ValidateConnectionString(updates); err != nil {
+		return err
+	}
 	return s.repo.Update(updates)
 }
 
+// FillConnectionString replaces the connection string with one generated
+// from the structured fields.
+func (s *InfrastructureService) FillConnectionString(infra *model.InfrastructureConfig) error {
+	conn, err := GenerateConnectionString(infra)
+	if err != nil {
+		return err
+	}
+	infra.ConnectionString = conn
+	return nil
+}
+
 func (s *InfrastructureService) Delete(env, key string) error {
 	return s.repo.Delete(env, key)
 }
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MaintenanceStatus is the global read-only switch. Unlike an environment
+// lock it freezes writes to every environment at once.
+type MaintenanceStatus struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty"`
+	EnabledBy string     `json:"enabledBy,omitempty"`
+	Since     *time.Time `json:"since,omitempty"`
+}
+
+// MaintenanceService stores maintenance mode in the database so that every
+// instance enforces it, and caches it for a short TTL. Toggling it on one
+// instance takes up to one TTL to reach the others.
+type MaintenanceService struct {
+	repo *repository.MaintenanceRepository
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	status   MaintenanceStatus
+	loadedAt time.Time
+}
+
+// NewMaintenanceService creates a MaintenanceService. A ttl of zero disables caching.
+func NewMaintenanceService(repo *repository.MaintenanceRepository, ttl time.Duration) *MaintenanceService {
+	return &MaintenanceService{repo: repo, ttl: ttl}
+}
+
+// Status returns the current state. If the database cannot be read it
+// returns the last state seen along with the error.
+func (s *MaintenanceService) Status() (MaintenanceStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < s.ttl {
+		return s.status, nil
+	}
+	m, err := s.repo.Get()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		m, err = &model.MaintenanceMode{}, nil
+	}
+	if err != nil {
+		return s.status, err
+	}
+	s.remember(m)
+	return s.status, nil
+}
+
+// Active reports whether writes are frozen, and the message to show callers.
+// It falls back to the last state seen when the database is unreachable.
+func (s *MaintenanceService) Active() (bool, string) {
+	st, _ := s.Status()
+	return st.Enabled, st.Message
+}
+
+func (s *MaintenanceService) Enable(by, message string) (MaintenanceStatus, error) {
+	now := time.Now()
+	return s.save(&model.MaintenanceMode{Enabled: true, Message: message, EnabledBy: by, Since: &now})
+}
+
+func (s *MaintenanceService) Disable() (MaintenanceStatus, error) {
+	return s.save(&model.MaintenanceMode{})
+}
+
+func (s *MaintenanceService) save(m *model.MaintenanceMode) (MaintenanceStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.repo.Save(m); err != nil {
+		return MaintenanceStatus{}, err
+	}
+	s.remember(m)
+	return s.status, nil
+}
+
+// remember caches m as the current state. The caller must hold s.mu.
+func (s *MaintenanceService) remember(m *model.MaintenanceMode) {
+	s.status = MaintenanceStatus{Enabled: m.Enabled, Message: m.Message, EnabledBy: m.EnabledBy, Since: m.Since}
+	s.loadedAt = time.Now()
+}
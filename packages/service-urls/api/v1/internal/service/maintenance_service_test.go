@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+func TestMaintenanceDefaultsToDisabled(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewMaintenanceService(repository.NewMaintenanceRepository(db), time.Minute)
+
+	mock.ExpectQuery("FROM `maintenance_modes`").WillReturnError(gorm.ErrRecordNotFound)
+
+	if enabled, _ := svc.Active(); enabled {
+		t.Error("expected maintenance to be off when it has never been set")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+}
+
+func TestMaintenanceIsReadFromDatabase(t *testing.T) {
+	db, mock := newMockDB(t)
+	// Another instance enabled maintenance; this one only sees it via the database.
+	mock.ExpectQuery("FROM `maintenance_modes`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "enabled", "message", "enabled_by"}).
+			AddRow(1, true, "database upgrade", "ops"))
+
+	svc := NewMaintenanceService(repository.NewMaintenanceRepository(db), time.Minute)
+	enabled, message := svc.Active()
+	if !enabled || message != "database upgrade" {
+		t.Errorf("expected maintenance from the database, got %v %q", enabled, message)
+	}
+
+	// Within the TTL the cached state is used without another query.
+	if enabled, _ := svc.Active(); !enabled {
+		t.Error("expected the cached state to be reused")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+}
+
+func TestMaintenanceEnablePersists(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewMaintenanceService(repository.NewMaintenanceRepository(db), time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `maintenance_modes` .* ON DUPLICATE KEY UPDATE").
+		WithArgs(1, true, "database upgrade", "ops", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	status, err := svc.Enable("ops", "database upgrade")
+	if err != nil {
+		t.Fatalf("Enable returned error: %v", err)
+	}
+	if !status.Enabled || status.EnabledBy != "ops" || status.Since == nil {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	// The write is visible on this instance without waiting for the TTL.
+	if enabled, _ := svc.Active(); !enabled {
+		t.Error("expected maintenance to be active after Enable")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+}
+
+func TestMaintenanceFallsBackToLastState(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewMaintenanceService(repository.NewMaintenanceRepository(db), 0)
+
+	mock.ExpectQuery("FROM `maintenance_modes`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "enabled", "message"}).AddRow(1, true, "database upgrade"))
+	mock.ExpectQuery("FROM `maintenance_modes`").WillReturnError(errors.New("dial tcp: connection refused"))
+
+	if enabled, _ := svc.Active(); !enabled {
+		t.Fatal("expected maintenance from the database")
+	}
+	if _, err := svc.Status(); err == nil {
+		t.Error("expected the read error to be reported")
+	}
+	if enabled, message := svc.Active(); !enabled || message != "database upgrade" {
+		t.Errorf("expected the last state when the database is unreachable, got %v %q", enabled, message)
+	}
+}
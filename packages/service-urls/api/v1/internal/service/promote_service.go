@@ -0,0 +1,285 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quckapp/service-urls-api/internal/model"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSameEnvironment     = errors.New("source and target environments must differ")
+	ErrPromoteKeysNotFound = errors.New("keys not found in source environment")
+)
+
+type PromoteAction string
+
+const (
+	PromoteActionCreated   PromoteAction = "created"
+	PromoteActionUpdated   PromoteAction = "updated"
+	PromoteActionUnchanged PromoteAction = "unchanged"
+)
+
+type PromoteRequest struct {
+	Source string   `json:"source" binding:"required"`
+	Target string   `json:"target" binding:"required"`
+	Keys   []string `json:"keys" binding:"required,min=1"`
+}
+
+type PromoteChange struct {
+	Kind     string        `json:"kind"`
+	Key      string        `json:"key"`
+	Action   PromoteAction `json:"action"`
+	OldValue string        `json:"oldValue,omitempty"`
+	NewValue string        `json:"newValue"`
+}
+
+type PromoteResult struct {
+	Source  string          `json:"source"`
+	Target  string          `json:"target"`
+	Changes []PromoteChange `json:"changes"`
+}
+
+// PromoteService copies selected keys from one environment to another.
+// Unlike Clone, only the named keys move, and all writes share a transaction.
+type PromoteService struct {
+	db *gorm.DB
+}
+
+func NewPromoteService(db *gorm.DB) *PromoteService {
+	return &PromoteService{db: db}
+}
+
+// Promote copies every service URL, infrastructure config and config entry
+// named in req.Keys from req.Source to req.Target. A key may match several
+// kinds; each is promoted. Secret values are copied unchanged and masked in
+// the result. Nothing is written if any key is missing from the source or
+// the target is locked. Each promotion is recorded in the audit table in the
+// same transaction.
+func (s *PromoteService) Promote(req *PromoteRequest, updatedBy string) (*PromoteResult, error) {
+	if req.Source == req.Target {
+		return nil, ErrSameEnvironment
+	}
+
+	result := &PromoteResult{Source: req.Source, Target: req.Target, Changes: []PromoteChange{}}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		lock, err := notFoundAsNil(repository.NewEnvironmentLockRepository(tx).FindByEnvForShare(req.Target))
+		if err != nil {
+			return fmt.Errorf("failed to check environment lock: %w", err)
+		}
+		if lock != nil {
+			return fmt.Errorf("%w by %s", ErrEnvironmentLocked, lock.LockedBy)
+		}
+
+		p := &promotion{
+			req:         req,
+			updatedBy:   updatedBy,
+			serviceRepo: repository.NewServiceUrlRepository(tx),
+			infraRepo:   repository.NewInfrastructureRepository(tx),
+			entryRepo:   repository.NewConfigEntryRepository(tx),
+		}
+		changes, err := p.run()
+		if err != nil {
+			return err
+		}
+
+		audit := &model.PromotionAudit{
+			SourceEnv:  req.Source,
+			TargetEnv:  req.Target,
+			Keys:       strings.Join(req.Keys, ","),
+			PromotedBy: updatedBy,
+		}
+		if err := repository.NewPromotionAuditRepository(tx).Create(audit); err != nil {
+			return fmt.Errorf("failed to record promotion: %w", err)
+		}
+		result.Changes = changes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type promotion struct {
+	req         *PromoteRequest
+	updatedBy   string
+	serviceRepo *repository.ServiceUrlRepository
+	infraRepo   *repository.InfrastructureRepository
+	entryRepo   *repository.ConfigEntryRepository
+}
+
+type promoteSource struct {
+	service *model.ServiceUrl
+	infra   *model.InfrastructureConfig
+	entry   *model.ConfigEntry
+}
+
+func (p *promotion) run() ([]PromoteChange, error) {
+	sources := make([]promoteSource, 0, len(p.req.Keys))
+	var missing []string
+	for _, key := range p.req.Keys {
+		src, err := p.loadSource(key)
+		if err != nil {
+			return nil, err
+		}
+		if src.service == nil && src.infra == nil && src.entry == nil {
+			missing = append(missing, key)
+			continue
+		}
+		sources = append(sources, src)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPromoteKeysNotFound, strings.Join(missing, ", "))
+	}
+
+	var changes []PromoteChange
+	for _, src := range sources {
+		if src.service != nil {
+			change, err := p.promoteService(src.service)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+		if src.infra != nil {
+			change, err := p.promoteInfra(src.infra)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+		if src.entry != nil {
+			change, err := p.promoteEntry(src.entry)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (p *promotion) loadSource(key string) (promoteSource, error) {
+	var src promoteSource
+	var err error
+	if src.service, err = notFoundAsNil(p.serviceRepo.FindByEnvAndKey(p.req.Source, key)); err != nil {
+		return src, fmt.Errorf("failed to load service %q: %w", key, err)
+	}
+	if src.infra, err = notFoundAsNil(p.infraRepo.FindByEnvAndKey(p.req.Source, key)); err != nil {
+		return src, fmt.Errorf("failed to load infrastructure %q: %w", key, err)
+	}
+	if src.entry, err = notFoundAsNil(p.entryRepo.FindByEnvAndKey(p.req.Source, key)); err != nil {
+		return src, fmt.Errorf("failed to load config entry %q: %w", key, err)
+	}
+	return src, nil
+}
+
+func (p *promotion) promoteService(src *model.ServiceUrl) (PromoteChange, error) {
+	change := PromoteChange{Kind: ImportKindService, Key: src.ServiceKey, NewValue: src.URL}
+	existing, err := notFoundAsNil(p.serviceRepo.FindByEnvAndKey(p.req.Target, src.ServiceKey))
+	if err != nil {
+		return change, err
+	}
+
+	if existing == nil {
+		clone := *src
+		clone.ID = uuid.Nil
+		clone.Environment = p.req.Target
+		clone.UpdatedBy = p.updatedBy
+		change.Action = PromoteActionCreated
+		return change, p.serviceRepo.Create(&clone)
+	}
+
+	change.OldValue = existing.URL
+	if existing.URL == src.URL && existing.Category == src.Category && existing.Description == src.Description {
+		change.Action = PromoteActionUnchanged
+		return change, nil
+	}
+	existing.URL = src.URL
+	existing.Category = src.Category
+	existing.Description = src.Description
+	existing.UpdatedBy = p.updatedBy
+	change.Action = PromoteActionUpdated
+	return change, p.serviceRepo.Update(existing)
+}
+
+func (p *promotion) promoteInfra(src *model.InfrastructureConfig) (PromoteChange, error) {
+	change := PromoteChange{Kind: ImportKindInfra, Key: src.InfraKey, NewValue: maskCredentials(infraValue(src))}
+	existing, err := notFoundAsNil(p.infraRepo.FindByEnvAndKey(p.req.Target, src.InfraKey))
+	if err != nil {
+		return change, err
+	}
+
+	if existing == nil {
+		clone := *src
+		clone.ID = uuid.Nil
+		clone.Environment = p.req.Target
+		clone.UpdatedBy = p.updatedBy
+		change.Action = PromoteActionCreated
+		return change, p.infraRepo.Create(&clone)
+	}
+
+	change.OldValue = maskCredentials(infraValue(existing))
+	if existing.Host == src.Host && existing.Port == src.Port && existing.Username == src.Username &&
+		existing.ConnectionString == src.ConnectionString {
+		change.Action = PromoteActionUnchanged
+		return change, nil
+	}
+	existing.Host = src.Host
+	existing.Port = src.Port
+	existing.Username = src.Username
+	existing.ConnectionString = src.ConnectionString
+	existing.UpdatedBy = p.updatedBy
+	change.Action = PromoteActionUpdated
+	return change, p.infraRepo.Update(existing)
+}
+
+func (p *promotion) promoteEntry(src *model.ConfigEntry) (PromoteChange, error) {
+	change := PromoteChange{Kind: ImportKindConfigEntry, Key: src.ConfigKey, NewValue: src.ConfigValue}
+	existing, err := notFoundAsNil(p.entryRepo.FindByEnvAndKey(p.req.Target, src.ConfigKey))
+	if err != nil {
+		return change, err
+	}
+	secret := src.IsSecret || (existing != nil && existing.IsSecret)
+
+	switch {
+	case existing == nil:
+		clone := *src
+		clone.ID = uuid.Nil
+		clone.Environment = p.req.Target
+		clone.UpdatedBy = p.updatedBy
+		change.Action = PromoteActionCreated
+		err = p.entryRepo.Create(&clone)
+	case existing.ConfigValue == src.ConfigValue && existing.Category == src.Category &&
+		existing.IsSecret == src.IsSecret && existing.Description == src.Description:
+		change.OldValue = existing.ConfigValue
+		change.Action = PromoteActionUnchanged
+	default:
+		change.OldValue = existing.ConfigValue
+		existing.ConfigValue = src.ConfigValue
+		existing.Category = src.Category
+		existing.IsSecret = src.IsSecret
+		existing.Description = src.Description
+		existing.UpdatedBy = p.updatedBy
+		change.Action = PromoteActionUpdated
+		err = p.entryRepo.Update(existing)
+	}
+
+	if secret {
+		change.NewValue = maskSecret(change.NewValue)
+		change.OldValue = maskSecret(change.OldValue)
+	}
+	return change, err
+}
+
+func notFoundAsNil[T any](v *T, err error) (*T, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return v, err
+}
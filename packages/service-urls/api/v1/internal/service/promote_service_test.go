@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func emptyRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id"})
+}
+
+func TestPromoteSelectedKeys(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewPromoteService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `environment_locks` WHERE environment = \\? .*FOR SHARE").
+		WithArgs("production").
+		WillReturnRows(emptyRows())
+
+	// Source lookups: AUTH_SERVICE_URL is a service URL, JWT_SECRET a config entry.
+	mock.ExpectQuery("FROM `service_urls` WHERE environment = \\? AND service_key = \\?").
+		WithArgs("staging", "AUTH_SERVICE_URL").
+		WillReturnRows(sqlmock.NewRows([]string{"environment", "service_key", "category", "url"}).
+			AddRow("staging", "AUTH_SERVICE_URL", "CORE", "http://auth.staging:8080"))
+	mock.ExpectQuery("FROM `infrastructure_configs` WHERE environment = \\? AND infra_key = \\?").
+		WithArgs("staging", "AUTH_SERVICE_URL").
+		WillReturnRows(emptyRows())
+	mock.ExpectQuery("FROM `config_entries` WHERE environment = \\? AND config_key = \\?").
+		WithArgs("staging", "AUTH_SERVICE_URL").
+		WillReturnRows(emptyRows())
+	mock.ExpectQuery("FROM `service_urls` WHERE environment = \\? AND service_key = \\?").
+		WithArgs("staging", "JWT_SECRET").
+		WillReturnRows(emptyRows())
+	mock.ExpectQuery("FROM `infrastructure_configs` WHERE environment = \\? AND infra_key = \\?").
+		WithArgs("staging", "JWT_SECRET").
+		WillReturnRows(emptyRows())
+	mock.ExpectQuery("FROM `config_entries` WHERE environment = \\? AND config_key = \\?").
+		WithArgs("staging", "JWT_SECRET").
+		WillReturnRows(sqlmock.NewRows([]string{"environment", "config_key", "category", "config_value", "is_secret"}).
+			AddRow("staging", "JWT_SECRET", "SECURITY", "enc:staging-secret", true))
+
+	// Target writes: the service URL is new, the secret differs.
+	mock.ExpectQuery("FROM `service_urls` WHERE environment = \\? AND service_key = \\?").
+		WithArgs("production", "AUTH_SERVICE_URL").
+		WillReturnRows(emptyRows())
+	mock.ExpectExec("INSERT INTO `service_urls`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("FROM `config_entries` WHERE environment = \\? AND config_key = \\?").
+		WithArgs("production", "JWT_SECRET").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "environment", "config_key", "category", "config_value", "is_secret"}).
+			AddRow("5b0c8f7e-0000-4000-8000-000000000001", "production", "JWT_SECRET", "SECURITY", "enc:prod-secret", true))
+	mock.ExpectExec("UPDATE `config_entries` SET .*`config_value`=\\?").
+		WithArgs("SECURITY", "enc:staging-secret", true, "", "ops", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `promotion_audits`").
+		WithArgs(sqlmock.AnyArg(), "staging", "production", "AUTH_SERVICE_URL,JWT_SECRET", "ops", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := svc.Promote(&PromoteRequest{
+		Source: "staging",
+		Target: "production",
+		Keys:   []string{"AUTH_SERVICE_URL", "JWT_SECRET"},
+	}, "ops")
+	if err != nil {
+		t.Fatalf("Promote returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+
+	if len(result.Changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", result.Changes)
+	}
+	svcChange, secretChange := result.Changes[0], result.Changes[1]
+	if svcChange.Kind != ImportKindService || svcChange.Action != PromoteActionCreated || svcChange.NewValue != "http://auth.staging:8080" {
+		t.Errorf("unexpected service change: %+v", svcChange)
+	}
+	if secretChange.Kind != ImportKindConfigEntry || secretChange.Action != PromoteActionUpdated {
+		t.Errorf("unexpected secret change: %+v", secretChange)
+	}
+	if secretChange.NewValue != "****" || secretChange.OldValue != "****" {
+		t.Errorf("expected secret values masked in result, got %+v", secretChange)
+	}
+}
+
+func TestPromoteRejectsLockedTarget(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewPromoteService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `environment_locks` WHERE environment = \\? .*FOR SHARE").
+		WithArgs("production").
+		WillReturnRows(sqlmock.NewRows([]string{"environment", "locked_by", "reason"}).
+			AddRow("production", "release-manager", "freeze"))
+	mock.ExpectRollback()
+
+	_, err := svc.Promote(&PromoteRequest{Source: "staging", Target: "production", Keys: []string{"AUTH_SERVICE_URL"}}, "ops")
+	if !errors.Is(err, ErrEnvironmentLocked) {
+		t.Fatalf("expected ErrEnvironmentLocked, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected no writes for a locked target: %v", err)
+	}
+}
+
+func TestPromoteRollsBackOnMissingKey(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := NewPromoteService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `environment_locks`").WillReturnRows(emptyRows())
+	mock.ExpectQuery("FROM `service_urls`").WillReturnRows(emptyRows())
+	mock.ExpectQuery("FROM `infrastructure_configs`").WillReturnRows(emptyRows())
+	mock.ExpectQuery("FROM `config_entries`").WillReturnRows(emptyRows())
+	mock.ExpectRollback()
+
+	_, err := svc.Promote(&PromoteRequest{Source: "qa", Target: "staging", Keys: []string{"NOPE"}}, "ops")
+	if !errors.Is(err, ErrPromoteKeysNotFound) {
+		t.Fatalf("expected ErrPromoteKeysNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+}
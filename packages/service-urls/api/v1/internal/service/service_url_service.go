@@ -18,6 +18,9 @@ func (s *ServiceUrlService) List(env, category string) ([]model.ServiceUrl, erro
 }
 
 func (s *ServiceUrlService) Create(svc *model.ServiceUrl) error {
+	if err := ValidateEnvKey("serviceKey", svc.ServiceKey); err != nil {
+		return err
+	}
 	return s.repo.Create(svc)
 }
 
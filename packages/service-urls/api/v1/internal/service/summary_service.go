@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quckapp/service-urls-api/internal/repository"
+)
+
+// Environments lists every environment shown on the admin dashboard, in display order.
+var Environments = []string{"local", "development", "qa", "uat1", "uat2", "uat3", "staging", "production", "live"}
+
+type EnvironmentSummary struct {
+	Environment      string  `json:"environment"`
+	ServiceCount     int64   `json:"serviceCount"`
+	InfraCount       int64   `json:"infraCount"`
+	ConfigEntryCount int64   `json:"configEntryCount"`
+	HasFirebase      bool    `json:"hasFirebase"`
+	LastUpdated      *string `json:"lastUpdated"`
+}
+
+// SummaryService builds the per-environment dashboard summary with one grouped
+// query per table, and caches the result for a short TTL.
+type SummaryService struct {
+	serviceUrlRepo  *repository.ServiceUrlRepository
+	infraRepo       *repository.InfrastructureRepository
+	firebaseRepo    *repository.FirebaseRepository
+	configEntryRepo *repository.ConfigEntryRepository
+	ttl             time.Duration
+
+	mu       sync.Mutex
+	cached   []EnvironmentSummary
+	cachedAt time.Time
+}
+
+// NewSummaryService creates a SummaryService. A ttl of zero disables caching.
+func NewSummaryService(
+	serviceUrlRepo *repository.ServiceUrlRepository,
+	infraRepo *repository.InfrastructureRepository,
+	firebaseRepo *repository.FirebaseRepository,
+	configEntryRepo *repository.ConfigEntryRepository,
+	ttl time.Duration,
+) *SummaryService {
+	return &SummaryService{
+		serviceUrlRepo:  serviceUrlRepo,
+		infraRepo:       infraRepo,
+		firebaseRepo:    firebaseRepo,
+		configEntryRepo: configEntryRepo,
+		ttl:             ttl,
+	}
+}
+
+func (s *SummaryService) GetSummaries() ([]EnvironmentSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cachedAt) < s.ttl {
+		return s.cached, nil
+	}
+
+	summaries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	s.cached = summaries
+	s.cachedAt = time.Now()
+	return summaries, nil
+}
+
+// Invalidate drops the cached summary so the next read hits the database.
+func (s *SummaryService) Invalidate() {
+	s.mu.Lock()
+	s.cached = nil
+	s.mu.Unlock()
+}
+
+func (s *SummaryService) load() ([]EnvironmentSummary, error) {
+	services, err := s.serviceUrlRepo.AggregateByEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate service urls: %w", err)
+	}
+	infra, err := s.infraRepo.AggregateByEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate infrastructure: %w", err)
+	}
+	entries, err := s.configEntryRepo.AggregateByEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate config entries: %w", err)
+	}
+	firebase, err := s.firebaseRepo.AggregateByEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate firebase configs: %w", err)
+	}
+
+	byEnv := make(map[string]*EnvironmentSummary, len(Environments))
+	lastUpdated := make(map[string]time.Time, len(Environments))
+	summaries := make([]EnvironmentSummary, len(Environments))
+	for i, env := range Environments {
+		summaries[i].Environment = env
+		byEnv[env] = &summaries[i]
+	}
+
+	apply := func(rows []repository.EnvAggregate, set func(*EnvironmentSummary, int64)) {
+		for _, row := range rows {
+			summary, ok := byEnv[row.Environment]
+			if !ok {
+				continue
+			}
+			set(summary, row.Count)
+			if row.LastUpdated != nil && row.LastUpdated.After(lastUpdated[row.Environment]) {
+				lastUpdated[row.Environment] = *row.LastUpdated
+			}
+		}
+	}
+	apply(services, func(e *EnvironmentSummary, n int64) { e.ServiceCount = n })
+	apply(infra, func(e *EnvironmentSummary, n int64) { e.InfraCount = n })
+	apply(entries, func(e *EnvironmentSummary, n int64) { e.ConfigEntryCount = n })
+	apply(firebase, func(e *EnvironmentSummary, n int64) { e.HasFirebase = n > 0 })
+
+	for env, t := range lastUpdated {
+		formatted := t.UTC().Format(time.RFC3339)
+		byEnv[env].LastUpdated = &formatted
+	}
+	return summaries, nil
+}
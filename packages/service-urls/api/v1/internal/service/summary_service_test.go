@@ -0,0 +1,137 @@
+package service
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/quckapp/service-urls-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+func newSummaryService(db *gorm.DB, ttl time.Duration) *SummaryService {
+	return NewSummaryService(
+		repository.NewServiceUrlRepository(db),
+		repository.NewInfrastructureRepository(db),
+		repository.NewFirebaseRepository(db),
+		repository.NewConfigEntryRepository(db),
+		ttl,
+	)
+}
+
+func aggregateRows(rows ...[]driver.Value) *sqlmock.Rows {
+	r := sqlmock.NewRows([]string{"environment", "count", "last_updated"})
+	for _, row := range rows {
+		r.AddRow(row...)
+	}
+	return r
+}
+
+func expectAggregates(mock sqlmock.Sqlmock, updated time.Time) {
+	mock.ExpectQuery("FROM `service_urls`.*GROUP BY `environment`").
+		WillReturnRows(aggregateRows(
+			[]driver.Value{"qa", 4, updated},
+			[]driver.Value{"production", 12, updated.Add(-time.Hour)},
+		))
+	mock.ExpectQuery("FROM `infrastructure_configs`.*GROUP BY `environment`").
+		WillReturnRows(aggregateRows([]driver.Value{"production", 3, updated}))
+	mock.ExpectQuery("FROM `config_entries`.*GROUP BY `environment`").
+		WillReturnRows(aggregateRows([]driver.Value{"qa", 7, updated.Add(-2 * time.Hour)}))
+	mock.ExpectQuery("FROM `firebase_configs`.*GROUP BY `environment`").
+		WillReturnRows(aggregateRows([]driver.Value{"production", 1, nil}))
+}
+
+func TestGetSummariesAggregatesAcrossEnvironments(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := newSummaryService(db, 0)
+
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expectAggregates(mock, updated)
+
+	summaries, err := svc.GetSummaries()
+	if err != nil {
+		t.Fatalf("GetSummaries returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+
+	if len(summaries) != len(Environments) {
+		t.Fatalf("expected %d summaries, got %d", len(Environments), len(summaries))
+	}
+	byEnv := make(map[string]EnvironmentSummary)
+	for _, s := range summaries {
+		byEnv[s.Environment] = s
+	}
+
+	qa := byEnv["qa"]
+	if qa.ServiceCount != 4 || qa.InfraCount != 0 || qa.ConfigEntryCount != 7 || qa.HasFirebase {
+		t.Errorf("unexpected qa summary: %+v", qa)
+	}
+	if qa.LastUpdated == nil || *qa.LastUpdated != updated.Format(time.RFC3339) {
+		t.Errorf("expected qa lastUpdated %s, got %v", updated.Format(time.RFC3339), qa.LastUpdated)
+	}
+
+	prod := byEnv["production"]
+	if prod.ServiceCount != 12 || prod.InfraCount != 3 || prod.ConfigEntryCount != 0 || !prod.HasFirebase {
+		t.Errorf("unexpected production summary: %+v", prod)
+	}
+
+	local := byEnv["local"]
+	if local.ServiceCount != 0 || local.HasFirebase || local.LastUpdated != nil {
+		t.Errorf("expected empty local summary, got %+v", local)
+	}
+}
+
+func TestGetSummariesServedFromCacheUntilInvalidated(t *testing.T) {
+	db, mock := newMockDB(t)
+	svc := newSummaryService(db, time.Minute)
+
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expectAggregates(mock, updated)
+	if _, err := svc.GetSummaries(); err != nil {
+		t.Fatalf("first GetSummaries returned error: %v", err)
+	}
+
+	// No new expectations: a second read must not touch the database.
+	if _, err := svc.GetSummaries(); err != nil {
+		t.Fatalf("cached GetSummaries returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+
+	svc.Invalidate()
+	expectAggregates(mock, updated)
+	if _, err := svc.GetSummaries(); err != nil {
+		t.Fatalf("GetSummaries after invalidate returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expected reload after invalidate: %v", err)
+	}
+}
+
+// BenchmarkGetSummaries reports database queries per uncached summary load. The
+// previous per-environment loop issued four queries for each of the nine environments.
+func BenchmarkGetSummaries(b *testing.B) {
+	db, mock := newMockDB(b)
+	svc := newSummaryService(db, 0)
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	queries := 0
+	if err := db.Callback().Row().Before("gorm:row").Register("count_queries", func(*gorm.DB) { queries++ }); err != nil {
+		b.Fatalf("failed to register callback: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		expectAggregates(mock, updated)
+		b.StartTimer()
+		if _, err := svc.GetSummaries(); err != nil {
+			b.Fatalf("GetSummaries returned error: %v", err)
+		}
+	}
+	b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+}